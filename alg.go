@@ -12,6 +12,8 @@ package gdal
 import "C"
 import (
 	"fmt"
+	"runtime"
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -23,9 +25,12 @@ func ComputeMedianCutPCT(
 	progress ProgressFunc,
 	data interface{},
 ) int {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(red)
+	defer runtime.KeepAlive(green)
+	defer runtime.KeepAlive(blue)
+	defer runtime.KeepAlive(ct)
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	err := C.GDALComputeMedianCutPCT(
 		red.cval,
@@ -34,8 +39,8 @@ func ComputeMedianCutPCT(
 		nil,
 		C.int(colors),
 		ct.cval,
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	)
 	return int(err)
 }
@@ -47,9 +52,13 @@ func DitherRGB2PCT(
 	progress ProgressFunc,
 	data interface{},
 ) int {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
-	}
+	defer runtime.KeepAlive(red)
+	defer runtime.KeepAlive(green)
+	defer runtime.KeepAlive(blue)
+	defer runtime.KeepAlive(target)
+	defer runtime.KeepAlive(ct)
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	err := C.GDALDitherRGB2PCT(
 		red.cval,
@@ -57,14 +66,18 @@ func DitherRGB2PCT(
 		blue.cval,
 		target.cval,
 		ct.cval,
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	)
 	return int(err)
 }
 
 // Compute checksum for image region
 func (rb RasterBand) Checksum(xOff, yOff, xSize, ySize int) int {
+	defer runtime.KeepAlive(rb)
+	if rb.closed() {
+		return 0
+	}
 	sum := C.GDALChecksumImage(rb.cval, C.int(xOff), C.int(yOff), C.int(xSize), C.int(ySize))
 	return int(sum)
 }
@@ -76,9 +89,14 @@ func (src RasterBand) ComputeProximity(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dest)
+	if src.closed() {
+		return ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -92,8 +110,8 @@ func (src RasterBand) ComputeProximity(
 		src.cval,
 		dest.cval,
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -106,9 +124,14 @@ func (src RasterBand) FillNoData(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	if src.closed() {
+		return ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -125,8 +148,8 @@ func (src RasterBand) FillNoData(
 		0,
 		C.int(iterations),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -139,9 +162,15 @@ func (src RasterBand) Polygonize(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(layer)
+	if src.closed() {
+		return ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -157,8 +186,8 @@ func (src RasterBand) Polygonize(
 		layer.cval,
 		C.int(fieldIndex),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -171,9 +200,15 @@ func (src RasterBand) FPolygonize(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(layer)
+	if src.closed() {
+		return ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -189,8 +224,8 @@ func (src RasterBand) FPolygonize(
 		layer.cval,
 		C.int(fieldIndex),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -202,9 +237,15 @@ func (src RasterBand) SieveFilter(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(mask)
+	defer runtime.KeepAlive(dest)
+	if src.closed() {
+		return ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	opts := make([]*C.char, length+1)
@@ -221,8 +262,8 @@ func (src RasterBand) SieveFilter(
 		C.int(threshold),
 		C.int(connectedness),
 		(**C.char)(unsafe.Pointer(&opts[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -230,42 +271,17 @@ func (src RasterBand) SieveFilter(
 /* Warp functions                                */
 /* --------------------------------------------- */
 
+// The transformers are implemented in transform.go
+
 //Unimplemented: CreateGenImgProjTransformer
-//Unimplemented: CreateGenImgProjTransformer2
 //Unimplemented: CreateGenImgProjTransformer3
 //Unimplemented: SetGenImgProjTransformerDstGeoTransform
-//Unimplemented: DestroyGenImgProjTransformer
-//Unimplemented: GenImgProjTransform
 
 //Unimplemented: CreateReprojectionTransformer
-//Unimplemented: DestroyReprojection
-//Unimplemented: ReprojectionTransform
-//Unimplemented: CreateGCPTransformer
 //Unimplemented: CreateGCPRefineTransformer
-//Unimplemented: DestroyGCPTransformer
-//Unimplemented: GCPTransform
-
-//Unimplemented: CreateTPSTransformer
-//Unimplemented: DestroyTPSTransformer
-//Unimplemented: TPSTransform
-
-//Unimplemented: CreateRPCTransformer
-//Unimplemented: DestroyRPCTransformer
-//Unimplemented: RPCTransform
-
-//Unimplemented: CreateGeoLocTransformer
-//Unimplemented: DestroyGeoLocTransformer
-//Unimplemented: GeoLocTransform
-
-//Unimplemented: CreateApproxTransformer
-//Unimplemented: DestroyApproxTransformer
-//Unimplemented: ApproxTransform
 
 //Unimplemented: SimpleImageWarp
-//Unimplemented: SuggestedWarpOutput
 //Unimplemented: SuggsetedWarpOutput2
-//Unimplemented: SerializeTransformer
-//Unimplemented: DeserializeTransformer
 
 //Unimplemented: TransformGeolocations
 
@@ -273,30 +289,384 @@ func (src RasterBand) SieveFilter(
 /* Contour line functions                        */
 /* --------------------------------------------- */
 
-//Unimplemented: CreateContourGenerator
-//Unimplemented: FeedLine
-//Unimplemented: Destroy
-//Unimplemented: ContourWriter
-//Unimplemented: ContourGenerate
+// ContourWriter receives the contour lines computed by a ContourGenerator.
+// The vertices are in pixel/line coordinates, the slices are only valid for
+// the duration of the call.  Returning an error stops the generation.
+type ContourWriter func(level float64, xs, ys []float64) error
+
+// ContourGenerator computes contour lines from scanlines fed one at a time
+type ContourGenerator struct {
+	cval   C.GDALContourGeneratorH
+	handle cgo.Handle
+	width  int
+}
+
+type contourState struct {
+	writer ContourWriter
+	err    error
+}
+
+//export goGDALContourWriterProxyA
+func goGDALContourWriterProxyA(level C.double, nPoints C.int, xs, ys *C.double, handle C.uintptr_t) C.int {
+	state := cgo.Handle(handle).Value().(*contourState)
+	n := int(nPoints)
+	var goXs, goYs []float64
+	if n > 0 {
+		goXs = unsafe.Slice((*float64)(unsafe.Pointer(xs)), n)
+		goYs = unsafe.Slice((*float64)(unsafe.Pointer(ys)), n)
+	}
+	if err := state.writer(float64(level), goXs, goYs); err != nil {
+		state.err = err
+		return C.CE_Failure
+	}
+	return C.CE_None
+}
+
+// Create a contour generator for a width by height surface, writing lines at
+// every multiple of interval offset by base to writer.  Pixels equal to noData
+// are skipped if useNoData is set.
+func CreateContourGenerator(
+	width, height int,
+	useNoData bool,
+	noData float64,
+	interval, base float64,
+	writer ContourWriter,
+) (*ContourGenerator, error) {
+	defer errorScope()()
+	handle := cgo.NewHandle(&contourState{writer: writer})
+	cg := C.goGDALContourGeneratorCreate(
+		C.int(width),
+		C.int(height),
+		BoolToCInt(useNoData),
+		C.double(noData),
+		C.double(interval),
+		C.double(base),
+		C.uintptr_t(handle),
+	)
+	if cg == nil {
+		handle.Delete()
+		return nil, lastError(CE_Failure)
+	}
+	return &ContourGenerator{cval: cg, handle: handle, width: width}, nil
+}
+
+// Feed the next scanline of the surface, which must hold width values.  The
+// error returned by the ContourWriter, if any, is returned.
+func (cg *ContourGenerator) FeedLine(line []float64) error {
+	defer errorScope()()
+	if cg == nil || cg.cval == nil {
+		return ErrClosed
+	}
+	if len(line) != cg.width {
+		return fmt.Errorf("scanline holds %d values, %d required", len(line), cg.width)
+	}
+	ret := C.GDAL_CG_FeedLine(cg.cval, (*C.double)(unsafe.Pointer(&line[0])))
+	// some GDAL versions ignore the failure of the writer
+	if err := cg.writerError(); err != nil {
+		return err
+	}
+	return ret.Err()
+}
+
+// writerError returns and clears the error returned by the ContourWriter
+func (cg *ContourGenerator) writerError() error {
+	state := cg.handle.Value().(*contourState)
+	err := state.err
+	state.err = nil
+	return err
+}
+
+// Close the contour generator, flushing the pending lines to the writer.  The
+// error returned by the ContourWriter while flushing, if any, is returned.
+func (cg *ContourGenerator) Close() error {
+	if cg == nil || cg.cval == nil {
+		return nil
+	}
+	C.GDAL_CG_Destroy(cg.cval)
+	cg.cval = nil
+	err := cg.writerError()
+	cg.handle.Delete()
+	return err
+}
+
+// Create vector contours from a raster band, written to dst.  Contours are
+// generated at every multiple of interval offset by base, or at the
+// fixedLevels if given.  idField and elevField are the indexes of the dst
+// fields receiving a unique identifier and the elevation of each contour,
+// -1 to leave them unset.
+func (src RasterBand) ContourGenerate(
+	interval, base float64,
+	fixedLevels []float64,
+	useNoData bool,
+	noData float64,
+	dst Layer,
+	idField, elevField int,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	if src.closed() {
+		return ErrClosed
+	}
+	var cFixedLevels *C.double
+	if len(fixedLevels) > 0 {
+		cFixedLevels = (*C.double)(unsafe.Pointer(&fixedLevels[0]))
+	}
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+
+	return C.GDALContourGenerate(
+		src.cval,
+		C.double(interval),
+		C.double(base),
+		C.int(len(fixedLevels)),
+		cFixedLevels,
+		BoolToCInt(useNoData),
+		C.double(noData),
+		unsafe.Pointer(dst.cval),
+		C.int(idField),
+		C.int(elevField),
+		cProgress,
+		cArg,
+	).Err()
+}
 
 /* --------------------------------------------- */
 /* Rasterizer functions                          */
 /* --------------------------------------------- */
 
-// Burn geometries into raster
-//Unimplmemented: RasterizeGeometries
+// The rasterizer functions accept these options:
+//
+//	ALL_TOUCHED=TRUE        burn all pixels touched by lines or polygons,
+//	                        not only those whose center is inside polygons
+//	BURN_VALUE_FROM=Z       burn the Z values of the geometries, added to
+//	                        the burn value
+//	MERGE_ALG=REPLACE/ADD   overwrite the existing values, or add to them
+//	ATTRIBUTE=name          (layers only) burn the value of the attribute
+//	                        field of each feature instead of a burn value
+
+// Burn geometries into raster.  The geometries are in the georeferenced
+// coordinates of the dataset.  burnValues holds one value per band for each
+// geometry, or one value per band used for all the geometries.
+func (dataset *Dataset) RasterizeGeometries(
+	bands []int,
+	geoms []Geometry,
+	burnValues []float64,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geoms)
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
+	if len(bands) == 0 || len(geoms) == 0 {
+		return nil
+	}
+	switch len(burnValues) {
+	case len(bands) * len(geoms):
+	case len(bands):
+		values := make([]float64, 0, len(bands)*len(geoms))
+		for range geoms {
+			values = append(values, burnValues...)
+		}
+		burnValues = values
+	default:
+		return fmt.Errorf("got %d burn values for %d bands and %d geometries", len(burnValues), len(bands), len(geoms))
+	}
+
+	cBands := make([]C.int, len(bands))
+	for i, band := range bands {
+		cBands[i] = C.int(band)
+	}
+	cGeoms := make([]C.OGRGeometryH, len(geoms))
+	for i, geom := range geoms {
+		if geom.closed() {
+			return ErrClosed
+		}
+		cGeoms[i] = geom.cval
+	}
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
-// Burn geometries from the specified list of layers into the raster
-//Unimplemented: RasterizeLayers
+	return C.GDALRasterizeGeometries(
+		dataset.cval,
+		C.int(len(bands)),
+		&cBands[0],
+		C.int(len(geoms)),
+		&cGeoms[0],
+		nil,
+		nil,
+		(*C.double)(unsafe.Pointer(&burnValues[0])),
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		cProgress,
+		cArg,
+	).Err()
+}
 
-// Burn geometries from the specified list of layers into the raster
-//Unimplemented: RasterizeLayersBuf
+// Burn geometries from the specified list of layers into the raster.  The
+// geometries are reprojected to the spatial reference of the dataset if
+// needed.  burnValues holds one value per band for each layer, or one value
+// per band used for all the layers, and may be nil if the ATTRIBUTE option is
+// given.
+func (dataset *Dataset) RasterizeLayers(
+	bands []int,
+	layers []Layer,
+	burnValues []float64,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layers)
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
+	if len(bands) == 0 || len(layers) == 0 {
+		return nil
+	}
+	switch len(burnValues) {
+	case 0, len(bands) * len(layers):
+	case len(bands):
+		values := make([]float64, 0, len(bands)*len(layers))
+		for range layers {
+			values = append(values, burnValues...)
+		}
+		burnValues = values
+	default:
+		return fmt.Errorf("got %d burn values for %d bands and %d layers", len(burnValues), len(bands), len(layers))
+	}
+
+	cBands := make([]C.int, len(bands))
+	for i, band := range bands {
+		cBands[i] = C.int(band)
+	}
+	cLayers := make([]C.OGRLayerH, len(layers))
+	for i, layer := range layers {
+		if layer.closed() {
+			return ErrClosed
+		}
+		cLayers[i] = layer.cval
+	}
+	var cBurnValues *C.double
+	if len(burnValues) > 0 {
+		cBurnValues = (*C.double)(unsafe.Pointer(&burnValues[0]))
+	}
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	return C.GDALRasterizeLayers(
+		dataset.cval,
+		C.int(len(bands)),
+		&cBands[0],
+		C.int(len(layers)),
+		&cLayers[0],
+		nil,
+		nil,
+		cBurnValues,
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		cProgress,
+		cArg,
+	).Err()
+}
+
+// Burn geometries from the specified list of layers into a buffer of
+// xSize*ySize pixels stored row by row.  The buffer is georeferenced by
+// projection, in WKT, and geoTransform, the geometries being reprojected to
+// it if needed.
+func RasterizeLayersBuf[T Numeric](
+	buf []T,
+	xSize, ySize int,
+	layers []Layer,
+	projection string,
+	geoTransform [6]float64,
+	burnValue float64,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layers)
+	if len(buf) != xSize*ySize {
+		return fmt.Errorf("buffer holds %d pixels, %dx%d required", len(buf), xSize, ySize)
+	}
+	if len(buf) == 0 || len(layers) == 0 {
+		return nil
+	}
+	dataType, err := dataTypeFor[T]()
+	if err != nil {
+		return err
+	}
+	cLayers := make([]C.OGRLayerH, len(layers))
+	for i, layer := range layers {
+		if layer.closed() {
+			return ErrClosed
+		}
+		cLayers[i] = layer.cval
+	}
+
+	cProjection := C.CString(projection)
+	defer C.free(unsafe.Pointer(cProjection))
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	return C.GDALRasterizeLayersBuf(
+		unsafe.Pointer(&buf[0]),
+		C.int(xSize),
+		C.int(ySize),
+		C.GDALDataType(dataType),
+		0,
+		0,
+		C.int(len(layers)),
+		&cLayers[0],
+		cProjection,
+		(*C.double)(unsafe.Pointer(&geoTransform[0])),
+		nil,
+		nil,
+		C.double(burnValue),
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		cProgress,
+		cArg,
+	).Err()
+}
 
 /* --------------------------------------------- */
 /* Gridding functions                            */
 /* --------------------------------------------- */
 
-// TODO(kyle): this is not right
 type GridAlgorithm uint8
 
 const (
@@ -313,7 +683,19 @@ const (
 	InverseDistanceToAPowerNearestNeighbor = GridAlgorithm(C.GGA_InverseDistanceToAPowerNearestNeighbor)
 )
 
-type GridOptions struct {
+// GridOptions selects a gridding algorithm and its parameters.  It is
+// implemented by InverseDistanceOptions, MovingAverageOptions,
+// NearestNeighborOptions, DataMetricsOptions and LinearOptions.
+type GridOptions interface {
+	// cOptions returns the algorithm and the matching GDALGrid*Options
+	cOptions() (GridAlgorithm, unsafe.Pointer, error)
+}
+
+// Radius1 and Radius2 are the semi-axes of the search ellipse, rotated by
+// Angle degrees counter clockwise, zero meaning that all points are used.
+
+// Options of the inverse distance to a power algorithm
+type InverseDistanceOptions struct {
 	Power           float64
 	Smoothing       float64
 	AnisotropyRatio float64
@@ -321,15 +703,132 @@ type GridOptions struct {
 	Radius1         float64
 	Radius2         float64
 	Angle           float64
-	MaxPoints       uint32
-	MinPoints       uint32
-	NoDataValue     float64
+	// Maximum number of points used, zero for no limit
+	MaxPoints uint32
+	// Minimum number of points found in the search ellipse, below which the
+	// pixel is set to NoDataValue
+	MinPoints   uint32
+	NoDataValue float64
 }
 
-// Fill selected raster regions by interpolation from the edges
+func (opts InverseDistanceOptions) cOptions() (GridAlgorithm, unsafe.Pointer, error) {
+	cOpts := new(C.GDALGridInverseDistanceToAPowerOptions)
+	C.goGDALSetGridOptionsSize(unsafe.Pointer(cOpts), C.sizeof_GDALGridInverseDistanceToAPowerOptions)
+	cOpts.dfPower = C.double(opts.Power)
+	cOpts.dfSmoothing = C.double(opts.Smoothing)
+	cOpts.dfAnisotropyRatio = C.double(opts.AnisotropyRatio)
+	cOpts.dfAnisotropyAngle = C.double(opts.AnisotropyAngle)
+	cOpts.dfRadius1 = C.double(opts.Radius1)
+	cOpts.dfRadius2 = C.double(opts.Radius2)
+	cOpts.dfAngle = C.double(opts.Angle)
+	cOpts.nMaxPoints = C.GUInt32(opts.MaxPoints)
+	cOpts.nMinPoints = C.GUInt32(opts.MinPoints)
+	cOpts.dfNoDataValue = C.double(opts.NoDataValue)
+	return InverseDistanceToAPower, unsafe.Pointer(cOpts), nil
+}
+
+// Options of the moving average algorithm
+type MovingAverageOptions struct {
+	Radius1 float64
+	Radius2 float64
+	Angle   float64
+	// Minimum number of points found in the search ellipse, below which the
+	// pixel is set to NoDataValue
+	MinPoints   uint32
+	NoDataValue float64
+}
+
+func (opts MovingAverageOptions) cOptions() (GridAlgorithm, unsafe.Pointer, error) {
+	cOpts := new(C.GDALGridMovingAverageOptions)
+	C.goGDALSetGridOptionsSize(unsafe.Pointer(cOpts), C.sizeof_GDALGridMovingAverageOptions)
+	cOpts.dfRadius1 = C.double(opts.Radius1)
+	cOpts.dfRadius2 = C.double(opts.Radius2)
+	cOpts.dfAngle = C.double(opts.Angle)
+	cOpts.nMinPoints = C.GUInt32(opts.MinPoints)
+	cOpts.dfNoDataValue = C.double(opts.NoDataValue)
+	return MovingAverage, unsafe.Pointer(cOpts), nil
+}
+
+// Options of the nearest neighbor algorithm
+type NearestNeighborOptions struct {
+	Radius1     float64
+	Radius2     float64
+	Angle       float64
+	NoDataValue float64
+}
+
+func (opts NearestNeighborOptions) cOptions() (GridAlgorithm, unsafe.Pointer, error) {
+	cOpts := new(C.GDALGridNearestNeighborOptions)
+	C.goGDALSetGridOptionsSize(unsafe.Pointer(cOpts), C.sizeof_GDALGridNearestNeighborOptions)
+	cOpts.dfRadius1 = C.double(opts.Radius1)
+	cOpts.dfRadius2 = C.double(opts.Radius2)
+	cOpts.dfAngle = C.double(opts.Angle)
+	cOpts.dfNoDataValue = C.double(opts.NoDataValue)
+	return NearestNeighbor, unsafe.Pointer(cOpts), nil
+}
+
+// Options of the data metrics algorithms
+type DataMetricsOptions struct {
+	// One of MetricMinimum, MetricMaximum, MetricRange, MetricCount,
+	// MetricAverageDistance or MetricAverageDistancePts
+	Metric  GridAlgorithm
+	Radius1 float64
+	Radius2 float64
+	Angle   float64
+	// Minimum number of points found in the search ellipse, below which the
+	// pixel is set to NoDataValue
+	MinPoints   uint32
+	NoDataValue float64
+}
+
+func (opts DataMetricsOptions) cOptions() (GridAlgorithm, unsafe.Pointer, error) {
+	if opts.Metric < MetricMinimum || opts.Metric > MetricAverageDistancePts {
+		return 0, nil, fmt.Errorf("invalid data metric %d", opts.Metric)
+	}
+	cOpts := new(C.GDALGridDataMetricsOptions)
+	C.goGDALSetGridOptionsSize(unsafe.Pointer(cOpts), C.sizeof_GDALGridDataMetricsOptions)
+	cOpts.dfRadius1 = C.double(opts.Radius1)
+	cOpts.dfRadius2 = C.double(opts.Radius2)
+	cOpts.dfAngle = C.double(opts.Angle)
+	cOpts.nMinPoints = C.GUInt32(opts.MinPoints)
+	cOpts.dfNoDataValue = C.double(opts.NoDataValue)
+	return opts.Metric, unsafe.Pointer(cOpts), nil
+}
+
+// Options of the linear algorithm, interpolating on a Delaunay triangulation
+// of the points
+type LinearOptions struct {
+	// Pixels outside of the triangulation take the value of the nearest point
+	// within Radius, or NoDataValue.  A negative Radius means infinity, zero
+	// always uses NoDataValue.
+	Radius      float64
+	NoDataValue float64
+}
+
+func (opts LinearOptions) cOptions() (GridAlgorithm, unsafe.Pointer, error) {
+	cOpts := new(C.GDALGridLinearOptions)
+	C.goGDALSetGridOptionsSize(unsafe.Pointer(cOpts), C.sizeof_GDALGridLinearOptions)
+	cOpts.dfRadius = C.double(opts.Radius)
+	cOpts.dfNoDataValue = C.double(opts.NoDataValue)
+	return Linear, unsafe.Pointer(cOpts), nil
+}
+
+// gridBuffer returns the address, length and data type of a Grid buffer
+func gridBuffer[T Numeric](data []T) (unsafe.Pointer, int, DataType, error) {
+	dataType, err := dataTypeFor[T]()
+	if err != nil {
+		return nil, 0, Unknown, err
+	}
+	if len(data) == 0 {
+		return nil, 0, Unknown, fmt.Errorf("Error: empty buffer")
+	}
+	return unsafe.Pointer(&data[0]), len(data), dataType, nil
+}
+
+// Create a regular grid from the scattered points x, y, z.  The buffer holds
+// xsize*ysize pixels, its first row lying at ymin.
 func Grid(
-	algorithm GridAlgorithm,
-	options interface{}, // maybe define an interface.
+	options GridOptions,
 	x, y, z []float64,
 	xmin, xmax float64,
 	ymin, ymax float64,
@@ -338,53 +837,71 @@ func Grid(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer errorScope()()
+	if len(x) == 0 || len(y) != len(x) || len(z) != len(x) {
+		return fmt.Errorf("Error: x, y and z must hold the same, non zero, number of points")
+	}
+	algorithm, cOpts, err := options.cOptions()
+	if err != nil {
+		return err
+	}
 
 	var dataPtr unsafe.Pointer
 	var dataType DataType
+	var length int
 	switch data := buffer.(type) {
 	case []int8:
-		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []uint8:
-		dataType = Byte
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []int16:
-		dataType = Int16
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []uint16:
-		dataType = UInt16
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []int32:
-		dataType = Int32
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []uint32:
-		dataType = UInt32
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
+	case []int64:
+		dataPtr, length, dataType, err = gridBuffer(data)
+	case []uint64:
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []float32:
-		dataType = Float32
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
 	case []float64:
-		dataType = Float64
-		dataPtr = unsafe.Pointer(&data[0])
+		dataPtr, length, dataType, err = gridBuffer(data)
+	case []complex64:
+		dataPtr, length, dataType, err = gridBuffer(data)
+	case []complex128:
+		dataPtr, length, dataType, err = gridBuffer(data)
 	default:
 		return fmt.Errorf("Error: buffer is not a valid data type (must be a valid numeric slice)")
 	}
+	if err != nil {
+		return err
+	}
+	if length != int(xsize)*int(ysize) {
+		return fmt.Errorf("Error: buffer holds %d pixels, %dx%d required", length, xsize, ysize)
+	}
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	return C.GDALGridCreate(
 		C.GDALGridAlgorithm(algorithm),
-		unsafe.Pointer(&options),
+		cOpts,
 		C.GUInt32(len(x)),
-		(*C.double)(&x[0]),
-		(*C.double)(&y[0]),
-		(*C.double)(&z[0]),
+		(*C.double)(unsafe.Pointer(&x[0])),
+		(*C.double)(unsafe.Pointer(&y[0])),
+		(*C.double)(unsafe.Pointer(&z[0])),
 		C.double(xmin), C.double(xmax),
 		C.double(ymin), C.double(ymax),
 		C.GUInt32(xsize),
 		C.GUInt32(ysize),
 		C.GDALDataType(dataType),
 		dataPtr,
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(dataPtr),
+		cProgress,
+		cArg,
 	).Err()
 }
 
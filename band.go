@@ -14,19 +14,28 @@ import "C"
 import (
 	"fmt"
 	"reflect"
+	"runtime"
 	"unsafe"
 )
 
 // Fetch the pixel data type for this band
 func (band *RasterBand) RasterDataType() DataType {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	return DataType(C.GDALGetRasterDataType(band.cval))
 }
 
 // Fetch the "natural" block size of this band
 func (band *RasterBand) BlockSize() (int, int) {
-	var xSize, ySize int
-	C.GDALGetBlockSize(band.cval, (*C.int)(unsafe.Pointer(&xSize)), (*C.int)(unsafe.Pointer(&ySize)))
-	return xSize, ySize
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, 0
+	}
+	var xSize, ySize C.int
+	C.GDALGetBlockSize(band.cval, &xSize, &ySize)
+	return int(xSize), int(ySize)
 }
 
 // Advise driver of upcoming read requests
@@ -35,6 +44,11 @@ func (band *RasterBand) AdviseRead(
 	dataType DataType,
 	options []string,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -59,11 +73,16 @@ func (band *RasterBand) IO(
 	bufXSize, bufYSize int,
 	pixelSpace, lineSpace int,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	var dataType DataType
 	var dataPtr unsafe.Pointer
 	switch data := buffer.(type) {
 	case []int8:
-		dataType = Byte
+		dataType = Int8
 		dataPtr = unsafe.Pointer(&data[0])
 	case []uint8:
 		dataType = Byte
@@ -80,14 +99,30 @@ func (band *RasterBand) IO(
 	case []uint32:
 		dataType = UInt32
 		dataPtr = unsafe.Pointer(&data[0])
+	case []int64:
+		dataType = Int64
+		dataPtr = unsafe.Pointer(&data[0])
+	case []uint64:
+		dataType = UInt64
+		dataPtr = unsafe.Pointer(&data[0])
 	case []float32:
 		dataType = Float32
 		dataPtr = unsafe.Pointer(&data[0])
 	case []float64:
 		dataType = Float64
+		dataPtr = unsafe.Pointer(&data[0])
+	case []complex64:
+		dataType = CFloat32
+		dataPtr = unsafe.Pointer(&data[0])
+	case []complex128:
+		dataType = CFloat64
+		dataPtr = unsafe.Pointer(&data[0])
 	default:
 		return fmt.Errorf("Error: buffer is not a valid data type (must be a valid numeric slice)")
 	}
+	if dataType == Unknown {
+		return fmt.Errorf("Error: %T buffers are not supported by GDAL %d.%d", buffer, VERSION_MAJOR, VERSION_MINOR)
+	}
 
 	return C.GDALRasterIO(
 		band.cval,
@@ -102,92 +137,158 @@ func (band *RasterBand) IO(
 
 // Read a block of image data efficiently
 func (band *RasterBand) ReadBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALReadBlock(band.cval, C.int(xOff), C.int(yOff), dataPtr).Err()
 }
 
 // Write a block of image data efficiently
 func (band *RasterBand) WriteBlock(xOff, yOff int, dataPtr unsafe.Pointer) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALWriteBlock(band.cval, C.int(xOff), C.int(yOff), dataPtr).Err()
 }
 
 // Fetch X size of raster
 func (band *RasterBand) XSize() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	xSize := C.GDALGetRasterBandXSize(band.cval)
 	return int(xSize)
 }
 
 // Fetch Y size of raster
 func (band *RasterBand) YSize() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	ySize := C.GDALGetRasterBandYSize(band.cval)
 	return int(ySize)
 }
 
 // Find out if we have update permission for this band
 func (band *RasterBand) GetAccess() Access {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	access := C.GDALGetRasterAccess(band.cval)
 	return Access(access)
 }
 
 // Fetch the band number of this raster band
 func (band *RasterBand) Band() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	bandNumber := C.GDALGetBandNumber(band.cval)
 	return int(bandNumber)
 }
 
-// Fetch the owning dataset handle
+// Fetch the owning dataset handle, borrowed from the dataset the band was
+// fetched from, so that closing it does nothing
 func (band *RasterBand) GetDataset() *Dataset {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	dataset := C.GDALGetBandDataset(band.cval)
-	return &Dataset{dataset}
+	return &Dataset{cval: dataset, parent: band.parent}
 }
 
 // How should this band be interpreted as color?
 func (band *RasterBand) ColorInterp() ColorInterp {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	colorInterp := C.GDALGetRasterColorInterpretation(band.cval)
 	return ColorInterp(colorInterp)
 }
 
 // Set color interpretation of the raster band
 func (band *RasterBand) SetColorInterp(colorInterp ColorInterp) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterColorInterpretation(band.cval, C.GDALColorInterp(colorInterp)).Err()
 }
 
 // Fetch the color table associated with this raster band
 func (band *RasterBand) ColorTable() *ColorTable {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	ct := C.GDALGetRasterColorTable(band.cval)
 	if ct == nil {
 		return nil
 	}
-	return &ColorTable{ct}
+	return &ColorTable{cval: ct, parent: band.parent}
 }
 
 // Set the raster color table for this raster band
 func (band *RasterBand) SetColorTable(colorTable ColorTable) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	defer runtime.KeepAlive(colorTable)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterColorTable(band.cval, colorTable.cval).Err()
 }
 
 // Check for arbitrary overviews
 func (band *RasterBand) HasArbitraryOverviews() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	yes := C.GDALHasArbitraryOverviews(band.cval)
 	return int(yes)
 }
 
 // Return the number of overview layers available
 func (band *RasterBand) OverviewCount() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	count := C.GDALGetOverviewCount(band.cval)
 	return int(count)
 }
 
 // Fetch overview raster band object
 func (band *RasterBand) Overview(level int) *RasterBand {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	overview := C.GDALGetOverview(band.cval, C.int(level))
 	if overview == nil {
 		return nil
 	}
-	return &RasterBand{overview}
+	return &RasterBand{cval: overview, parent: band.parent}
 }
 
 // Fetch the no data value for this band
 func (band *RasterBand) NoDataValue() (val float64, valid bool) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, false
+	}
 	var success int
 	noDataVal := C.GDALGetRasterNoDataValue(band.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(noDataVal), success != 0
@@ -195,11 +296,20 @@ func (band *RasterBand) NoDataValue() (val float64, valid bool) {
 
 // Set the no data value for this band
 func (band *RasterBand) SetNoDataValue(val float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterNoDataValue(band.cval, C.double(val)).Err()
 }
 
 // Fetch the list of category names for this raster
 func (band *RasterBand) CategoryNames() []string {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	p := C.GDALGetRasterCategoryNames(band.cval)
 	var strings []string
 	q := uintptr(unsafe.Pointer(p))
@@ -217,6 +327,11 @@ func (band *RasterBand) CategoryNames() []string {
 
 // Set the category names for this band
 func (band *RasterBand) SetRasterCategoryNames(names []string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	length := len(names)
 	cStrings := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -230,6 +345,10 @@ func (band *RasterBand) SetRasterCategoryNames(names []string) error {
 
 // Fetch the minimum value for this band
 func (band *RasterBand) GetMinimum() (val float64, valid bool) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, false
+	}
 	var success int
 	min := C.GDALGetRasterMinimum(band.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(min), success != 0
@@ -237,6 +356,10 @@ func (band *RasterBand) GetMinimum() (val float64, valid bool) {
 
 // Fetch the maximum value for this band
 func (band *RasterBand) GetMaximum() (val float64, valid bool) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, false
+	}
 	var success int
 	max := C.GDALGetRasterMaximum(band.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(max), success != 0
@@ -244,6 +367,10 @@ func (band *RasterBand) GetMaximum() (val float64, valid bool) {
 
 // Fetch image statistics
 func (band *RasterBand) GetStatistics(approxOK, force int) (min, max, mean, stdDev float64) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, 0, 0, 0
+	}
 	C.GDALGetRasterStatistics(
 		band.cval,
 		C.int(approxOK),
@@ -262,7 +389,12 @@ func (band *RasterBand) ComputeStatistics(
 	progress ProgressFunc,
 	data interface{},
 ) (min, max, mean, stdDev float64) {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, 0, 0, 0
+	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	C.GDALComputeRasterStatistics(
 		band.cval,
@@ -271,14 +403,19 @@ func (band *RasterBand) ComputeStatistics(
 		(*C.double)(unsafe.Pointer(&max)),
 		(*C.double)(unsafe.Pointer(&mean)),
 		(*C.double)(unsafe.Pointer(&stdDev)),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	)
 	return min, max, mean, stdDev
 }
 
 // Set statistics on raster band
 func (band *RasterBand) SetStatistics(min, max, mean, stdDev float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterStatistics(
 		band.cval,
 		C.double(min),
@@ -290,12 +427,21 @@ func (band *RasterBand) SetStatistics(min, max, mean, stdDev float64) error {
 
 // Return raster unit type
 func (band *RasterBand) GetUnitType() string {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ""
+	}
 	cString := C.GDALGetRasterUnitType(band.cval)
 	return C.GoString(cString)
 }
 
 // Set unit type
 func (band *RasterBand) SetUnitType(unit string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	cString := C.CString(unit)
 	defer C.free(unsafe.Pointer(cString))
 
@@ -304,6 +450,10 @@ func (band *RasterBand) SetUnitType(unit string) error {
 
 // Fetch the raster value offset
 func (band *RasterBand) GetOffset() (float64, bool) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, false
+	}
 	var success int
 	val := C.GDALGetRasterOffset(band.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), success != 0
@@ -311,11 +461,20 @@ func (band *RasterBand) GetOffset() (float64, bool) {
 
 // Set scaling offset
 func (band *RasterBand) SetOffset(offset float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterOffset(band.cval, C.double(offset)).Err()
 }
 
 // Fetch the raster value scale
 func (band *RasterBand) GetScale() (float64, bool) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, false
+	}
 	var success int
 	val := C.GDALGetRasterScale(band.cval, (*C.int)(unsafe.Pointer(&success)))
 	return float64(val), success != 0
@@ -323,11 +482,20 @@ func (band *RasterBand) GetScale() (float64, bool) {
 
 // Set scaling ratio
 func (band *RasterBand) SetScale(scale float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetRasterScale(band.cval, C.double(scale)).Err()
 }
 
 // Compute the min / max values for a band
 func (band *RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0, 0
+	}
 	var minmax [2]float64
 	C.GDALComputeRasterMinMax(
 		band.cval,
@@ -338,6 +506,10 @@ func (band *RasterBand) ComputeMinMax(approxOK int) (min, max float64) {
 
 // Get Band Metadata
 func (band *RasterBand) Metadata(domain string) []string {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	cDomain := C.CString(domain)
 	cObject := C.GDALMajorObjectH(unsafe.Pointer(band.cval))
 	p := C.GDALGetMetadata(cObject, cDomain)
@@ -356,6 +528,10 @@ func (band *RasterBand) Metadata(domain string) []string {
 
 // Flush raster data cache
 func (band *RasterBand) FlushCache() {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return
+	}
 	C.GDALFlushRasterCache(band.cval)
 }
 
@@ -367,9 +543,13 @@ func (rb RasterBand) Histogram(
 	progress ProgressFunc,
 	data interface{},
 ) ([]uint64, error) {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(rb)
+	if rb.closed() {
+		return nil, ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	histogram := make([]C.GUIntBig, buckets)
 	var err error
@@ -381,8 +561,8 @@ func (rb RasterBand) Histogram(
 		(*C.GUIntBig)(unsafe.Pointer(&histogram[0])),
 		C.int(includeOutOfRange),
 		C.int(approxOK),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err(); err != nil {
 		return nil, err
 	} else {
@@ -397,9 +577,13 @@ func (rb RasterBand) DefaultHistogram(
 	progress ProgressFunc,
 	data interface{},
 ) (min, max float64, buckets int, histogram []uint64, err error) {
-	arg := &goGDALProgressFuncProxyArgs{
-		progress, data,
+	defer errorScope()()
+	defer runtime.KeepAlive(rb)
+	if rb.closed() {
+		return 0, 0, 0, nil, ErrClosed
 	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	var cHistogram *C.GUIntBig
 
@@ -410,8 +594,8 @@ func (rb RasterBand) DefaultHistogram(
 		(*C.int)(unsafe.Pointer(&buckets)),
 		&cHistogram,
 		C.int(force),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 
 	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&histogram))
@@ -432,6 +616,11 @@ func (rb RasterBand) DefaultHistogram(
 
 // Fill this band with a constant value
 func (band *RasterBand) Fill(real, imaginary float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALFillRaster(band.cval, C.double(real), C.double(imaginary)).Err()
 }
 
@@ -441,12 +630,22 @@ func (band *RasterBand) Fill(real, imaginary float64) error {
 
 // Fetch default Raster Attribute Table
 func (band *RasterBand) GetDefaultRAT() RasterAttributeTable {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return RasterAttributeTable{}
+	}
 	rat := C.GDALGetDefaultRAT(band.cval)
-	return RasterAttributeTable{rat}
+	return RasterAttributeTable{cval: rat, parent: band.parent}
 }
 
 // Set default Raster Attribute Table
 func (band *RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	defer runtime.KeepAlive(rat)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetDefaultRAT(band.cval, rat.cval).Err()
 }
 
@@ -454,21 +653,34 @@ func (band *RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
 
 // Return the mask band associated with the band
 func (band *RasterBand) GetMaskBand() *RasterBand {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return nil
+	}
 	mask := C.GDALGetMaskBand(band.cval)
 	if mask == nil {
 		return nil
 	}
-	return &RasterBand{mask}
+	return &RasterBand{cval: mask, parent: band.parent}
 }
 
 // Return the status flags of the mask band associated with the band
 func (band *RasterBand) GetMaskFlags() int {
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return 0
+	}
 	flags := C.GDALGetMaskFlags(band.cval)
 	return int(flags)
 }
 
 // Adds a mask band to the current band
 func (band *RasterBand) CreateMaskBand(flags int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
 	return C.GDALCreateMaskBand(band.cval, C.int(flags)).Err()
 }
 
@@ -479,7 +691,14 @@ func (sourceRaster *RasterBand) RasterBandCopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer errorScope()()
+	defer runtime.KeepAlive(sourceRaster)
+	defer runtime.KeepAlive(destRaster)
+	if sourceRaster.closed() {
+		return ErrClosed
+	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
@@ -493,7 +712,7 @@ func (sourceRaster *RasterBand) RasterBandCopyWholeRaster(
 		sourceRaster.cval,
 		destRaster.cval,
 		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
@@ -0,0 +1,221 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// Block is one block of the "natural" block grid of a raster band.  X and Y
+// index the block in the grid, the offsets and sizes give the pixels it
+// covers.  Blocks on the right and bottom edges of the raster are clipped to
+// its extent, so their size can be smaller than the band block size.
+type Block struct {
+	X, Y         int
+	XOff, YOff   int
+	XSize, YSize int
+}
+
+// Window returns the region of the raster covered by the block
+func (blk Block) Window() Window {
+	return Window{XOff: blk.XOff, YOff: blk.YOff, XSize: blk.XSize, YSize: blk.YSize}
+}
+
+// Blocks returns an iterator over the blocks of the band, row by row.
+func (band *RasterBand) Blocks() iter.Seq[Block] {
+	if band.closed() {
+		return func(func(Block) bool) {}
+	}
+	return blockGrid(band.XSize(), band.YSize(), band)
+}
+
+func blockGrid(xSize, ySize int, band *RasterBand) iter.Seq[Block] {
+	blockXSize, blockYSize := band.BlockSize()
+	return func(yield func(Block) bool) {
+		if blockXSize <= 0 || blockYSize <= 0 {
+			return
+		}
+		for y, yOff := 0, 0; yOff < ySize; y, yOff = y+1, yOff+blockYSize {
+			for x, xOff := 0, 0; xOff < xSize; x, xOff = x+1, xOff+blockXSize {
+				blk := Block{
+					X: x, Y: y,
+					XOff: xOff, YOff: yOff,
+					XSize: blockXSize, YSize: blockYSize,
+				}
+				if xOff+blk.XSize > xSize {
+					blk.XSize = xSize - xOff
+				}
+				if yOff+blk.YSize > ySize {
+					blk.YSize = ySize - yOff
+				}
+				if !yield(blk) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// BlockBuffer is a block along with its pixels, stored row by row.
+type BlockBuffer[T Numeric] struct {
+	Block
+	Data []T
+}
+
+// ReadBlocks returns an iterator reading the blocks of the band in turn.  The
+// iteration stops after the first read error, which is yielded along with the
+// block that failed.
+func ReadBlocks[T Numeric](band *RasterBand) iter.Seq2[BlockBuffer[T], error] {
+	return func(yield func(BlockBuffer[T], error) bool) {
+		for blk := range band.Blocks() {
+			data, err := ReadWindow[T](band, blk.Window())
+			if !yield(BlockBuffer[T]{Block: blk, Data: data}, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// BlockFunc computes the out buffers of a block from its in buffers.  The
+// buffers are indexed like the bands given to ProcessBlocks.
+type BlockFunc[T Numeric] func(blk Block, in, out [][]T) error
+
+// ErrBandMismatch is returned by ProcessBlocks when the bands do not all have
+// the same size.
+var ErrBandMismatch = errors.New("bands differ in size")
+
+// ProcessBlocks runs fn over every block of the in bands, using the block
+// grid of the first in band (or out band if there are none), and writes the
+// results to the out bands.  Blocks are processed by workers goroutines in
+// parallel, workers <= 0 meaning one per CPU.
+//
+// Reads and writes are serialized per dataset, as GDAL datasets must not be
+// accessed from several threads at once, but fn itself runs concurrently.
+// Processing stops at the first error returned by fn or GDAL, or when ctx is
+// cancelled, and that error is returned.
+func ProcessBlocks[T Numeric](
+	ctx context.Context,
+	in, out []*RasterBand,
+	workers int,
+	fn BlockFunc[T],
+) error {
+	bands := append(append([]*RasterBand{}, in...), out...)
+	if len(bands) == 0 {
+		return nil
+	}
+	for _, band := range bands {
+		if band.closed() {
+			return ErrClosed
+		}
+	}
+	xSize, ySize := bands[0].XSize(), bands[0].YSize()
+	locks := make(map[C.GDALDatasetH]*sync.Mutex)
+	bandLocks := make(map[*RasterBand]*sync.Mutex)
+	for _, band := range bands {
+		if band.XSize() != xSize || band.YSize() != ySize {
+			return ErrBandMismatch
+		}
+		ds := band.GetDataset().cval
+		if locks[ds] == nil {
+			locks[ds] = &sync.Mutex{}
+		}
+		bandLocks[band] = locks[ds]
+	}
+	lockFor := func(band *RasterBand) *sync.Mutex {
+		return bandLocks[band]
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	process := func(blk Block) error {
+		win := blk.Window()
+		inBufs := make([][]T, len(in))
+		for i, band := range in {
+			inBufs[i] = make([]T, win.Len())
+			mu := lockFor(band)
+			mu.Lock()
+			err := ReadWindowInto(band, win, inBufs[i])
+			mu.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+		outBufs := make([][]T, len(out))
+		for i := range out {
+			outBufs[i] = make([]T, win.Len())
+		}
+		if err := fn(blk, inBufs, outBufs); err != nil {
+			return err
+		}
+		for i, band := range out {
+			mu := lockFor(band)
+			mu.Lock()
+			err := WriteWindow(band, win, outBufs[i])
+			mu.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	blocks := make(chan Block)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for blk := range blocks {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := process(blk); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for blk := range blockGrid(xSize, ySize, bands[0]) {
+		select {
+		case blocks <- blk:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(blocks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
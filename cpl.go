@@ -12,6 +12,9 @@ package gdal
 */
 import "C"
 import (
+	"runtime"
+	"runtime/cgo"
+	"sync"
 	"unsafe"
 )
 
@@ -102,8 +105,204 @@ func PushQuietHandler() {
 	C.CPLPushErrorHandler(C.CPLErrorHandler(C.CPLQuietErrorHandler))
 }
 
+// ErrorHandler receives the messages emitted by GDAL through CPLError().
+type ErrorHandler func(class ErrorClass, num int, msg string)
+
+// errorHandlers holds the handles of the ErrorHandlers currently installed in
+// GDAL, so that they can be released once they are uninstalled.
+var errorHandlers = struct {
+	sync.Mutex
+	handles map[cgo.Handle]struct{}
+	global  cgo.Handle
+}{handles: make(map[cgo.Handle]struct{})}
+
+//export goGDALErrorHandlerProxyA
+func goGDALErrorHandlerProxyA(class, num C.int, msg *C.char, handle C.uintptr_t) {
+	handler := cgo.Handle(handle).Value().(ErrorHandler)
+	handler(ErrorClass(class), int(num), C.GoString(msg))
+}
+
+// PushErrorHandler installs handler on top of GDAL's error handler stack.  It
+// receives every debug message, warning and error emitted by GDAL until it is
+// uninstalled with PopHandler().
+//
+// Like the rest of the handler stack, the handler is local to the OS thread
+// it is pushed from.  Callers that need it to stay in effect across several
+// GDAL calls should lock their goroutine with runtime.LockOSThread(), or use
+// SetErrorHandler() instead.
+func PushErrorHandler(handler ErrorHandler) {
+	h := cgo.NewHandle(handler)
+	errorHandlers.Lock()
+	errorHandlers.handles[h] = struct{}{}
+	errorHandlers.Unlock()
+	C.goGDALPushErrorHandler(C.uintptr_t(h))
+}
+
 // PopHandler pops the current error handler off of the error handling function
 // stack.
 func PopHandler() {
-	C.CPLPopErrorHandler()
+	h := cgo.Handle(C.goGDALPopErrorHandler())
+	errorHandlers.Lock()
+	defer errorHandlers.Unlock()
+	if _, ok := errorHandlers.handles[h]; ok {
+		delete(errorHandlers.handles, h)
+		h.Delete()
+	}
+}
+
+// SetErrorHandler installs handler as the global error handler, used by all
+// threads that do not have a handler pushed on their stack.  Passing nil
+// restores GDAL's default handler, which prints messages to stderr.
+func SetErrorHandler(handler ErrorHandler) {
+	errorHandlers.Lock()
+	defer errorHandlers.Unlock()
+	var h cgo.Handle
+	if handler != nil {
+		h = cgo.NewHandle(handler)
+	}
+	C.goGDALSetErrorHandler(C.uintptr_t(h))
+	if errorHandlers.global != 0 {
+		errorHandlers.global.Delete()
+	}
+	errorHandlers.global = h
+}
+
+// CollectWarnings calls fn with an error handler installed that records the
+// warnings emitted by GDAL during the call, and returns them along with the
+// error returned by fn.  The warnings are not printed out.
+//
+// The calling goroutine is locked to its OS thread while fn runs, so only
+// GDAL calls made by fn from that goroutine are observed.
+func CollectWarnings(fn func() error) ([]*Error, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var warnings []*Error
+	PushErrorHandler(func(class ErrorClass, num int, msg string) {
+		if class == CE_Warning {
+			warnings = append(warnings, &Error{Class: class, Num: num, Msg: msg})
+		}
+	})
+	defer PopHandler()
+	err := fn()
+	return warnings, err
+}
+
+// ErrorClass is the severity attached to a message reported through CPLError().
+type ErrorClass int
+
+const (
+	CE_None    = ErrorClass(C.CE_None)
+	CE_Debug   = ErrorClass(C.CE_Debug)
+	CE_Warning = ErrorClass(C.CE_Warning)
+	CE_Failure = ErrorClass(C.CE_Failure)
+	CE_Fatal   = ErrorClass(C.CE_Fatal)
+)
+
+// String returns a human readable name for the error class
+func (class ErrorClass) String() string {
+	switch class {
+	case CE_None:
+		return "None"
+	case CE_Debug:
+		return "Debug"
+	case CE_Warning:
+		return "Warning"
+	case CE_Failure:
+		return "Failure"
+	case CE_Fatal:
+		return "Fatal"
+	}
+	return "Illegal"
+}
+
+// sentinel returns the package level error value matching the class.
+func (class ErrorClass) sentinel() error {
+	switch class {
+	case CE_Debug:
+		return ErrDebug
+	case CE_Warning:
+		return ErrWarning
+	case CE_Failure:
+		return ErrFailure
+	case CE_Fatal:
+		return ErrFatal
+	}
+	return ErrIllegal
+}
+
+// Error is an error reported by GDAL or OGR.  It carries the class and CPL
+// error number of the failure along with the message GDAL emitted for it, as
+// returned by CPLGetLastErrorMsg().
+//
+// Error values match the ErrDebug, ErrWarning, ErrFailure, ErrFatal and
+// ErrIllegal sentinels with errors.Is(), depending on their class.  Errors
+// returned from OGR calls additionally match the corresponding ErrOGR*
+// sentinel.
+type Error struct {
+	// Class is the severity of the error
+	Class ErrorClass
+	// Num is the CPLErrorNum of the error, such as CPLE_OpenFailed
+	Num int
+	// Msg is the message reported by GDAL, if any
+	Msg string
+
+	// ogrErr is the OGRErr code returned by the failing OGR call, 0 for
+	// errors originating from a CPLErr.
+	ogrErr int
+}
+
+// CPLE_NotSupported is the Num of the errors returned by functions that need a
+// more recent GDAL than the one the package is built against.
+const CPLE_NotSupported = int(C.CPLE_NotSupported)
+
+// errorScope locks the calling goroutine to its OS thread and resets the CPL
+// error state of the thread, which GDAL keeps per thread.  Errors read with
+// lastError() or Err() until the returned function is called are thus those
+// of the GDAL calls made in the scope, and not left over from earlier calls or
+// recorded on another thread.  Functions reading GDAL errors open a scope on
+// entry:
+//
+//	defer errorScope()()
+func errorScope() func() {
+	runtime.LockOSThread()
+	C.CPLErrorReset()
+	return runtime.UnlockOSThread
+}
+
+// lastError builds an *Error of the given class from the last error recorded
+// by GDAL for the calling thread, and resets the error state so that the
+// message is not reported twice.  It must be called within an errorScope().
+func lastError(class ErrorClass) *Error {
+	e := &Error{Class: class}
+	if ErrorClass(C.CPLGetLastErrorType()) != CE_None {
+		e.Num = int(C.CPLGetLastErrorNo())
+		e.Msg = C.GoString(C.CPLGetLastErrorMsg())
+	}
+	C.CPLErrorReset()
+	return e
+}
+
+// Error returns the GDAL message, falling back to a generic description of
+// the class when GDAL did not record one.
+func (e *Error) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.ogrErr != 0 {
+		return ogrSentinel(e.ogrErr).Error()
+	}
+	return e.Class.sentinel().Error()
+}
+
+// Is reports whether target is one of the sentinel errors matching e.
+func (e *Error) Is(target error) bool {
+	if e.ogrErr != 0 {
+		return target == ogrSentinel(e.ogrErr) || target == ogrClassSentinel(e.ogrErr)
+	}
+	if e.Class == CE_Fatal && target == ErrFailure {
+		// CE_Fatal was historically reported as ErrFailure
+		return true
+	}
+	return target == e.Class.sentinel()
 }
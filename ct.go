@@ -11,42 +11,89 @@ package gdal
 #cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
 */
 import "C"
+import (
+	"runtime"
+)
 
 /* ==================================================================== */
 /*      Color tables.                                                   */
 /* ==================================================================== */
 
+// newColorTable returns a color table owned by the caller
+func newColorTable(ct C.GDALColorTableH) ColorTable {
+	if ct == nil {
+		return ColorTable{}
+	}
+	return ColorTable{
+		cval: ct,
+		life: newLifetime(func() { C.GDALDestroyColorTable(ct) }),
+	}
+}
+
 // Construct a new color table
 func CreateColorTable(interp PaletteInterp) ColorTable {
 	ct := C.GDALCreateColorTable(C.GDALPaletteInterp(interp))
-	return ColorTable{ct}
+	return newColorTable(ct)
 }
 
 // Destroy the color table
 func (ct ColorTable) Destroy() {
-	C.GDALDestroyColorTable(ct.cval)
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return
+	}
+	if ct.life.release() {
+		C.GDALDestroyColorTable(ct.cval)
+	}
+}
+
+// Close destroys the color table if it is owned by the caller.  Closing a
+// color table that is already closed does nothing.
+func (ct *ColorTable) Close() error {
+	defer runtime.KeepAlive(ct)
+	if ct.cval != nil && ct.life.owned() && ct.life.release() {
+		C.GDALDestroyColorTable(ct.cval)
+	}
+	ct.cval = nil
+	return nil
 }
 
 // Make a copy of the color table
 func (ct ColorTable) Clone() ColorTable {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return ColorTable{}
+	}
 	newCT := C.GDALCloneColorTable(ct.cval)
-	return ColorTable{newCT}
+	return newColorTable(newCT)
 }
 
 // Fetch palette interpretation
 func (ct ColorTable) PaletteInterpretation() PaletteInterp {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return 0
+	}
 	pi := C.GDALGetPaletteInterpretation(ct.cval)
 	return PaletteInterp(pi)
 }
 
 // Get number of color entries in table
 func (ct ColorTable) EntryCount() int {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return 0
+	}
 	count := C.GDALGetColorEntryCount(ct.cval)
 	return int(count)
 }
 
 // Fetch a color entry from table
 func (ct ColorTable) Entry(index int) ColorEntry {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return ColorEntry{}
+	}
 	entry := C.GDALGetColorEntry(ct.cval, C.int(index))
 	return ColorEntry{entry}
 }
@@ -55,10 +102,18 @@ func (ct ColorTable) Entry(index int) ColorEntry {
 
 // Set entry in color table
 func (ct ColorTable) SetEntry(index int, entry ColorEntry) {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return
+	}
 	C.GDALSetColorEntry(ct.cval, C.int(index), entry.cval)
 }
 
 // Create color ramp
 func (ct ColorTable) CreateColorRamp(start, end int, startColor, endColor ColorEntry) {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return
+	}
 	C.GDALCreateColorRamp(ct.cval, C.int(start), startColor.cval, C.int(end), endColor.cval)
 }
@@ -9,6 +9,8 @@ The documentation is fairly limited, but the functionality fairly closely matche
 
 This wrapper has most recently been tested on Windows7, using the MinGW32_x64 compiler and GDAL version 1.11.
 
+GDAL 3.0 or later is required.  The few functions needing a later version return an error of number CPLE_NotSupported when built against an older GDAL.
+
 Usage
 
 A simple program to create a georeferenced blank 256x256 GeoTIFF:
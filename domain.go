@@ -0,0 +1,348 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"math"
+	"runtime"
+	"unsafe"
+)
+
+// Type of field domain
+type FieldDomainType int
+
+const (
+	FDT_Coded = FieldDomainType(C.OFDT_CODED)
+	FDT_Range = FieldDomainType(C.OFDT_RANGE)
+	FDT_Glob  = FieldDomainType(C.OFDT_GLOB)
+)
+
+// FieldDomain restricts the values of the fields it is attached to, through
+// FieldDefinition.SetDomainName.  Field domains are registered on a dataset.
+// They require GDAL 3.3, and listing, deleting or updating the field domains
+// of a dataset GDAL 3.5.
+type FieldDomain struct {
+	cval   C.OGRFieldDomainH
+	life   *lifetime
+	parent *lifetime
+}
+
+// CodedValue is an entry of a coded field domain
+type CodedValue struct {
+	Code  string
+	Value string
+}
+
+func newFieldDomain(domain C.OGRFieldDomainH) (FieldDomain, error) {
+	if domain == nil {
+		return FieldDomain{}, lastError(CE_Failure)
+	}
+	return FieldDomain{
+		cval: domain,
+		life: newLifetime(func() { C.OGR_FldDomain_Destroy(domain) }),
+	}, nil
+}
+
+// Create a coded field domain, whose values are one of the codes
+func CreateCodedFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	values []CodedValue,
+) (FieldDomain, error) {
+	defer errorScope()()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+
+	// the enumeration is terminated by a NULL code
+	cValues := make([]C.OGRCodedValue, len(values)+1)
+	for i, v := range values {
+		cValues[i].pszCode = C.CString(v.Code)
+		defer C.free(unsafe.Pointer(cValues[i].pszCode))
+		cValues[i].pszValue = C.CString(v.Value)
+		defer C.free(unsafe.Pointer(cValues[i].pszValue))
+	}
+
+	return newFieldDomain(C.OGR_CodedFldDomain_Create(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		&cValues[0],
+	))
+}
+
+// Create a range field domain for a numeric field type (FT_Integer,
+// FT_Integer64 or FT_Real).  An infinite bound leaves the range unbounded on
+// that side.
+func CreateRangeFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	min float64, minIsInclusive bool,
+	max float64, maxIsInclusive bool,
+) (FieldDomain, error) {
+	defer errorScope()()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+
+	return newFieldDomain(C.goGDALRangeFldDomainCreate(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		C.double(min),
+		BoolToCInt(minIsInclusive),
+		C.double(max),
+		BoolToCInt(maxIsInclusive),
+	))
+}
+
+// Create a glob field domain, whose values match a glob expression such as
+// "[A-Z]*"
+func CreateGlobFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	glob string,
+) (FieldDomain, error) {
+	defer errorScope()()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+	cGlob := C.CString(glob)
+	defer C.free(unsafe.Pointer(cGlob))
+
+	return newFieldDomain(C.OGR_GlobFldDomain_Create(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		cGlob,
+	))
+}
+
+// Destroy the field domain
+func (domain FieldDomain) Destroy() {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return
+	}
+	if domain.life.release() {
+		C.OGR_FldDomain_Destroy(domain.cval)
+	}
+}
+
+// Close destroys the field domain if it is owned by the caller
+func (domain *FieldDomain) Close() error {
+	defer runtime.KeepAlive(domain)
+	if domain.cval != nil && domain.life.owned() && domain.life.release() {
+		C.OGR_FldDomain_Destroy(domain.cval)
+	}
+	domain.cval = nil
+	return nil
+}
+
+// Fetch the name of the field domain
+func (domain FieldDomain) Name() string {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return ""
+	}
+	name := C.OGR_FldDomain_GetName(domain.cval)
+	return C.GoString(name)
+}
+
+// Fetch the description of the field domain
+func (domain FieldDomain) Description() string {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return ""
+	}
+	description := C.OGR_FldDomain_GetDescription(domain.cval)
+	return C.GoString(description)
+}
+
+// Fetch the type of the field domain
+func (domain FieldDomain) Type() FieldDomainType {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return 0
+	}
+	domainType := C.OGR_FldDomain_GetDomainType(domain.cval)
+	return FieldDomainType(domainType)
+}
+
+// Fetch the type of the fields the domain applies to
+func (domain FieldDomain) FieldType() FieldType {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return 0
+	}
+	fieldType := C.OGR_FldDomain_GetFieldType(domain.cval)
+	return FieldType(fieldType)
+}
+
+// Fetch the subtype of the fields the domain applies to
+func (domain FieldDomain) FieldSubType() FieldSubType {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return 0
+	}
+	subType := C.OGR_FldDomain_GetFieldSubType(domain.cval)
+	return FieldSubType(subType)
+}
+
+// Fetch the values of a coded field domain
+func (domain FieldDomain) CodedValues() []CodedValue {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return nil
+	}
+	p := C.OGR_CodedFldDomain_GetEnumeration(domain.cval)
+	if p == nil {
+		return nil
+	}
+	var values []CodedValue
+	for ; p.pszCode != nil; p = (*C.OGRCodedValue)(unsafe.Add(unsafe.Pointer(p), C.sizeof_OGRCodedValue)) {
+		values = append(values, CodedValue{
+			Code:  C.GoString(p.pszCode),
+			Value: C.GoString(p.pszValue),
+		})
+	}
+	return values
+}
+
+// Fetch the bounds of a numeric range field domain, infinite when unbounded
+func (domain FieldDomain) Range() (min float64, minIsInclusive bool, max float64, maxIsInclusive bool) {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return math.Inf(-1), false, math.Inf(1), false
+	}
+	var cMin, cMax C.double
+	var cMinIncl, cMaxIncl C.int
+	C.goGDALRangeFldDomainGetBounds(domain.cval, &cMin, &cMinIncl, &cMax, &cMaxIncl)
+	return float64(cMin), cMinIncl != 0, float64(cMax), cMaxIncl != 0
+}
+
+// Fetch the glob expression of a glob field domain
+func (domain FieldDomain) Glob() string {
+	defer runtime.KeepAlive(domain)
+	if domain.closed() {
+		return ""
+	}
+	glob := C.OGR_GlobFldDomain_GetGlob(domain.cval)
+	return C.GoString(glob)
+}
+
+/* -------------------------------------------------------------------- */
+/*      Dataset field domains                                           */
+/* -------------------------------------------------------------------- */
+
+// Fetch the names of the field domains of the dataset
+func (ds *Dataset) FieldDomainNames() []string {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return nil
+	}
+	p := C.GDALDatasetGetFieldDomainNames(ds.cval, nil)
+	if p == nil {
+		return nil
+	}
+	defer C.CSLDestroy(p)
+
+	names := make([]string, C.CSLCount(p))
+	for i, name := range unsafe.Slice(p, len(names)) {
+		names[i] = C.GoString(name)
+	}
+	return names
+}
+
+// Fetch a field domain of the dataset by name.  The field domain remains
+// owned by the dataset.
+func (ds *Dataset) FieldDomain(name string) (FieldDomain, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return FieldDomain{}, ErrClosed
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	domain := C.GDALDatasetGetFieldDomain(ds.cval, cName)
+	if domain == nil {
+		return FieldDomain{}, lastError(CE_Failure)
+	}
+	return FieldDomain{cval: domain, parent: ds.owner()}, nil
+}
+
+// Add a field domain to the dataset, which copies it
+func (ds *Dataset) AddFieldDomain(domain FieldDomain) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(domain)
+	if ds.closed() || domain.closed() {
+		return ErrClosed
+	}
+	var reason *C.char
+	if C.GDALDatasetAddFieldDomain(ds.cval, domain.cval, &reason) {
+		return nil
+	}
+	return fieldDomainError(reason)
+}
+
+// Delete a field domain of the dataset
+func (ds *Dataset) DeleteFieldDomain(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return ErrClosed
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	var reason *C.char
+	if C.GDALDatasetDeleteFieldDomain(ds.cval, cName, &reason) {
+		return nil
+	}
+	return fieldDomainError(reason)
+}
+
+// Replace the field domain of the dataset with the same name
+func (ds *Dataset) UpdateFieldDomain(domain FieldDomain) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(domain)
+	if ds.closed() || domain.closed() {
+		return ErrClosed
+	}
+	var reason *C.char
+	if C.GDALDatasetUpdateFieldDomain(ds.cval, domain.cval, &reason) {
+		return nil
+	}
+	return fieldDomainError(reason)
+}
+
+// fieldDomainError returns the error of a failed field domain operation,
+// freeing the failure reason given by GDAL
+func fieldDomainError(reason *C.char) error {
+	err := lastError(CE_Failure)
+	if reason != nil {
+		defer C.VSIFree(unsafe.Pointer(reason))
+		if err.Msg == "" {
+			err.Msg = C.GoString(reason)
+		}
+	}
+	return err
+}
@@ -11,9 +11,10 @@ package gdal
 #cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
 */
 import "C"
-
 import (
 	"errors"
+	"iter"
+	"runtime"
 	"unsafe"
 )
 
@@ -44,18 +45,42 @@ func GetDriver(index int) (*Driver, error) {
 	return &Driver{driver}, nil
 }
 
+// Drivers returns an iterator over the registered drivers
+func Drivers() iter.Seq[*Driver] {
+	return func(yield func(*Driver) bool) {
+		for i := 0; i < GetDriverCount(); i++ {
+			driver, err := GetDriver(i)
+			if err != nil {
+				return
+			}
+			if !yield(driver) {
+				return
+			}
+		}
+	}
+}
+
 // Destroy a GDAL driver
 func (driver *Driver) Destroy() {
+	if driver.closed() {
+		return
+	}
 	C.GDALDestroyDriver(driver.cval)
 }
 
 // Registers a driver for use
 func (driver *Driver) Register() int {
+	if driver.closed() {
+		return 0
+	}
 	return int(C.GDALRegisterDriver(driver.cval))
 }
 
 // Deregister the driver
 func (driver *Driver) Deregister() {
+	if driver.closed() {
+		return
+	}
 	C.GDALDeregisterDriver(driver.cval)
 }
 
@@ -66,6 +91,10 @@ func DestroyDriverManager() {
 
 // Delete named dataset
 func (driver *Driver) DeleteDataset(name string) error {
+	defer errorScope()()
+	if driver.closed() {
+		return ErrClosed
+	}
 	cDriver := driver.cval
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
@@ -74,6 +103,10 @@ func (driver *Driver) DeleteDataset(name string) error {
 
 // Rename named dataset
 func (driver *Driver) RenameDataset(newName, oldName string) error {
+	defer errorScope()()
+	if driver.closed() {
+		return ErrClosed
+	}
 	cDriver := driver.cval
 	cNewName := C.CString(newName)
 	defer C.free(unsafe.Pointer(cNewName))
@@ -84,6 +117,10 @@ func (driver *Driver) RenameDataset(newName, oldName string) error {
 
 // Copy all files associated with the named dataset
 func (driver *Driver) CopyDatasetFiles(newName, oldName string) error {
+	defer errorScope()()
+	if driver.closed() {
+		return ErrClosed
+	}
 	cDriver := driver.cval
 	cNewName := C.CString(newName)
 	defer C.free(unsafe.Pointer(cNewName))
@@ -94,12 +131,18 @@ func (driver *Driver) CopyDatasetFiles(newName, oldName string) error {
 
 // Get the short name associated with this driver
 func (driver *Driver) ShortName() string {
+	if driver.closed() {
+		return ""
+	}
 	cDriver := driver.cval
 	return C.GoString(C.GDALGetDriverShortName(cDriver))
 }
 
 // Get the long name associated with this driver
 func (driver *Driver) LongName() string {
+	if driver.closed() {
+		return ""
+	}
 	return C.GoString(C.GDALGetDriverLongName(driver.cval))
 }
 
@@ -110,6 +153,9 @@ func (driver *Driver) Create(
 	dataType DataType,
 	options []string,
 ) *Dataset {
+	if driver.closed() {
+		return nil
+	}
 	name := C.CString(filename)
 	defer C.free(unsafe.Pointer(name))
 
@@ -131,7 +177,7 @@ func (driver *Driver) Create(
 	if h == nil {
 		return nil
 	}
-	return &Dataset{h}
+	return newDataset(h)
 }
 
 // Create a copy of a dataset
@@ -143,6 +189,10 @@ func (driver *Driver) CreateCopy(
 	progress ProgressFunc,
 	data interface{},
 ) *Dataset {
+	defer runtime.KeepAlive(sourceDataset)
+	if driver.closed() {
+		return nil
+	}
 	name := C.CString(filename)
 	defer C.free(unsafe.Pointer(name))
 
@@ -154,33 +204,20 @@ func (driver *Driver) CreateCopy(
 	}
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
-	var h C.GDALDatasetH
-
-	if progress == nil {
-		h = C.GDALCreateCopy(
-			driver.cval, name,
-			sourceDataset.cval,
-			C.int(strict),
-			(**C.char)(unsafe.Pointer(&opts[0])),
-			nil,
-			nil,
-		)
-	} else {
-		arg := &goGDALProgressFuncProxyArgs{
-			progress, data,
-		}
-		h = C.GDALCreateCopy(
-			driver.cval, name,
-			sourceDataset.cval,
-			C.int(strict), (**C.char)(unsafe.Pointer(&opts[0])),
-			C.goGDALProgressFuncProxyB(),
-			unsafe.Pointer(arg),
-		)
-	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+
+	h := C.GDALCreateCopy(
+		driver.cval, name,
+		sourceDataset.cval,
+		C.int(strict), (**C.char)(unsafe.Pointer(&opts[0])),
+		cProgress,
+		cArg,
+	)
 	if h == nil {
 		return nil
 	}
-	return &Dataset{h}
+	return newDataset(h)
 }
 
 // Return the driver needed to access the provided dataset name.
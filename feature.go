@@ -12,79 +12,230 @@ package gdal
 import "C"
 import (
 	"reflect"
+	"runtime"
 	"time"
 	"unsafe"
 )
 
 type Feature struct {
 	cval C.OGRFeatureH
+	life *lifetime
+}
+
+// newFeature returns a feature owned by the caller
+func newFeature(feature C.OGRFeatureH) *Feature {
+	return &Feature{
+		cval: feature,
+		life: newLifetime(func() { C.OGR_F_Destroy(feature) }),
+	}
 }
 
 // Create a feature from this feature definition
 func (fd FeatureDefinition) Create() Feature {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return Feature{}
+	}
 	feature := C.OGR_F_Create(fd.cval)
-	return Feature{feature}
+	return *newFeature(feature)
+}
+
+// Keep takes ownership of a feature yielded by Layer.Features, so that it is
+// not destroyed when the loop body returns.  The caller must destroy it.
+func (feature Feature) Keep() Feature {
+	if feature.life != nil {
+		feature.life.kept = true
+	}
+	return feature
 }
 
 // Destroy this feature
 func (feature Feature) Destroy() {
-	C.OGR_F_Destroy(feature.cval)
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	if feature.life.release() {
+		C.OGR_F_Destroy(feature.cval)
+	}
+}
+
+// Close destroys the feature if it is owned by the caller.  Closing a feature
+// that is already closed does nothing.
+func (feature *Feature) Close() error {
+	defer runtime.KeepAlive(feature)
+	if feature.cval != nil && feature.life.owned() && feature.life.release() {
+		C.OGR_F_Destroy(feature.cval)
+	}
+	feature.cval = nil
+	return nil
 }
 
 // Fetch feature definition
 func (feature Feature) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return FeatureDefinition{}
+	}
 	fd := C.OGR_F_GetDefnRef(feature.cval)
-	return FeatureDefinition{fd}
+	return FeatureDefinition{cval: fd, parent: feature.life}
 }
 
 // Set feature geometry
 func (feature Feature) SetGeometry(geom Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	if feature.closed() {
+		return ErrClosed
+	}
 	return C.OGR_F_SetGeometry(feature.cval, geom.cval).Err()
 }
 
 // Set feature geometry, passing ownership to the feature
 func (feature Feature) SetGeometryDirectly(geom Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	if feature.closed() || geom.closed() || !geom.life.release() {
+		return ErrClosed
+	}
 	return C.OGR_F_SetGeometryDirectly(feature.cval, geom.cval).Err()
 }
 
-// Fetch geometry of this feature
+// Fetch geometry of this feature, which remains owned by the feature
 func (feature Feature) Geometry() Geometry {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return Geometry{}
+	}
 	geom := C.OGR_F_GetGeometryRef(feature.cval)
-	return Geometry{geom}
+	return Geometry{cval: geom, parent: feature.life}
+}
+
+// Fetch the number of geometry fields of this feature
+func (feature Feature) GeometryFieldCount() int {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
+	count := C.OGR_F_GetGeomFieldCount(feature.cval)
+	return int(count)
+}
+
+// Fetch definition for the indicated geometry field
+func (feature Feature) GeometryFieldDefinition(index int) GeometryFieldDefinition {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return GeometryFieldDefinition{}
+	}
+	defn := C.OGR_F_GetGeomFieldDefnRef(feature.cval, C.int(index))
+	return GeometryFieldDefinition{cval: defn, parent: feature.life}
+}
+
+// Fetch the geometry field index for the given field name
+func (feature Feature) GeometryFieldIndex(name string) int {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return -1
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	index := C.OGR_F_GetGeomFieldIndex(feature.cval, cName)
+	return int(index)
+}
+
+// Fetch the geometry of the indicated geometry field, which remains owned by
+// the feature
+func (feature Feature) GeometryField(index int) Geometry {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return Geometry{}
+	}
+	geom := C.OGR_F_GetGeomFieldRef(feature.cval, C.int(index))
+	return Geometry{cval: geom, parent: feature.life}
+}
+
+// Set the geometry of the indicated geometry field
+func (feature Feature) SetGeometryField(index int, geom Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	if feature.closed() {
+		return ErrClosed
+	}
+	return C.OGR_F_SetGeomField(feature.cval, C.int(index), geom.cval).Err()
+}
+
+// Set the geometry of the indicated geometry field, passing ownership to the
+// feature
+func (feature Feature) SetGeometryFieldDirectly(index int, geom Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(feature)
+	defer runtime.KeepAlive(geom)
+	if feature.closed() || geom.closed() || !geom.life.release() {
+		return ErrClosed
+	}
+	return C.OGR_F_SetGeomFieldDirectly(feature.cval, C.int(index), geom.cval).Err()
 }
 
 // Fetch geometry of this feature and assume ownership
 func (feature Feature) StealGeometry() Geometry {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return Geometry{}
+	}
 	geom := C.OGR_F_StealGeometry(feature.cval)
-	return Geometry{geom}
+	return newGeometry(geom)
 }
 
 // Duplicate feature
 func (feature Feature) Clone() Feature {
-	newFeature := C.OGR_F_Clone(feature.cval)
-	return Feature{newFeature}
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return Feature{}
+	}
+	clone := C.OGR_F_Clone(feature.cval)
+	return *newFeature(clone)
 }
 
 // Test if two features are the same
 func (f1 Feature) Equal(f2 Feature) bool {
+	defer runtime.KeepAlive(f1)
+	defer runtime.KeepAlive(f2)
+	if f1.closed() {
+		return false
+	}
 	equal := C.OGR_F_Equal(f1.cval, f2.cval)
 	return equal != 0
 }
 
 // Fetch number of fields on this feature
 func (feature Feature) FieldCount() int {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
 	count := C.OGR_F_GetFieldCount(feature.cval)
 	return int(count)
 }
 
 // Fetch definition for the indicated field
 func (feature Feature) FieldDefinition(index int) FieldDefinition {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return FieldDefinition{}
+	}
 	defn := C.OGR_F_GetFieldDefnRef(feature.cval, C.int(index))
-	return FieldDefinition{defn}
+	return FieldDefinition{cval: defn, parent: feature.life}
 }
 
 // Fetch the field index for the given field name
 func (feature Feature) FieldIndex(name string) int {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return -1
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_F_GetFieldIndex(feature.cval, cName)
@@ -93,53 +244,144 @@ func (feature Feature) FieldIndex(name string) int {
 
 // Return if a field has ever been assigned a value
 func (feature Feature) IsFieldSet(index int) bool {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return false
+	}
 	set := C.OGR_F_IsFieldSet(feature.cval, C.int(index))
 	return set != 0
 }
 
 // Clear a field and mark it as unset
 func (feature Feature) UnsetField(index int) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	C.OGR_F_UnsetField(feature.cval, C.int(index))
 }
 
+// Test if a field is null
+func (feature Feature) IsFieldNull(index int) bool {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return false
+	}
+	null := C.OGR_F_IsFieldNull(feature.cval, C.int(index))
+	return null != 0
+}
+
+// Test if a field is set and not null
+func (feature Feature) IsFieldSetAndNotNull(index int) bool {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return false
+	}
+	set := C.OGR_F_IsFieldSetAndNotNull(feature.cval, C.int(index))
+	return set != 0
+}
+
+// Clear a field and mark it as null
+func (feature Feature) SetFieldNull(index int) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	C.OGR_F_SetFieldNull(feature.cval, C.int(index))
+}
+
 // Fetch a reference to the internal field value
 func (feature Feature) RawField(index int) Field {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return Field{}
+	}
 	field := C.OGR_F_GetRawFieldRef(feature.cval, C.int(index))
 	return Field{field}
 }
 
 // Fetch field value as integer
 func (feature Feature) FieldAsInteger(index int) int {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
 	val := C.OGR_F_GetFieldAsInteger(feature.cval, C.int(index))
 	return int(val)
 }
 
+// Fetch field value as 64 bit integer
+func (feature Feature) FieldAsInteger64(index int) int64 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
+	val := C.OGR_F_GetFieldAsInteger64(feature.cval, C.int(index))
+	return int64(val)
+}
+
 // Fetch field value as float64
 func (feature Feature) FieldAsFloat64(index int) float64 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
 	val := C.OGR_F_GetFieldAsDouble(feature.cval, C.int(index))
 	return float64(val)
 }
 
 // Fetch field value as string
 func (feature Feature) FieldAsString(index int) string {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return ""
+	}
 	val := C.OGR_F_GetFieldAsString(feature.cval, C.int(index))
 	return C.GoString(val)
 }
 
 // Fetch field as list of integers
 func (feature Feature) FieldAsIntegerList(index int) []int {
-	var count int
-	cArray := C.OGR_F_GetFieldAsIntegerList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
-	var goSlice []int
-	header := (*reflect.SliceHeader)(unsafe.Pointer(&goSlice))
-	header.Cap = count
-	header.Len = count
-	header.Data = uintptr(unsafe.Pointer(cArray))
-	return goSlice
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return nil
+	}
+	var count C.int
+	cArray := C.OGR_F_GetFieldAsIntegerList(feature.cval, C.int(index), &count)
+	if count == 0 {
+		return nil
+	}
+	values := make([]int, count)
+	for i, v := range unsafe.Slice(cArray, count) {
+		values[i] = int(v)
+	}
+	return values
+}
+
+// Fetch field as list of 64 bit integers
+func (feature Feature) FieldAsInteger64List(index int) []int64 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return nil
+	}
+	var count C.int
+	cArray := C.OGR_F_GetFieldAsInteger64List(feature.cval, C.int(index), &count)
+	if count == 0 {
+		return nil
+	}
+	values := make([]int64, count)
+	for i, v := range unsafe.Slice(cArray, count) {
+		values[i] = int64(v)
+	}
+	return values
 }
 
 // Fetch field as list of float64
 func (feature Feature) FieldAsFloat64List(index int) []float64 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return nil
+	}
 	var count int
 	cArray := C.OGR_F_GetFieldAsDoubleList(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []float64
@@ -152,6 +394,10 @@ func (feature Feature) FieldAsFloat64List(index int) []float64 {
 
 // Fetch field as list of strings
 func (feature Feature) FieldAsStringList(index int) []string {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return nil
+	}
 	p := C.OGR_F_GetFieldAsStringList(feature.cval, C.int(index))
 
 	var strings []string
@@ -170,6 +416,10 @@ func (feature Feature) FieldAsStringList(index int) []string {
 
 // Fetch field as binary data
 func (feature Feature) FieldAsBinary(index int) []uint8 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return nil
+	}
 	var count int
 	cArray := C.OGR_F_GetFieldAsBinary(feature.cval, C.int(index), (*C.int)(unsafe.Pointer(&count)))
 	var goSlice []uint8
@@ -182,6 +432,10 @@ func (feature Feature) FieldAsBinary(index int) []uint8 {
 
 // Fetch field as date and time
 func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return time.Time{}, false
+	}
 	var year, month, day, hour, minute, second, tzFlag int
 	success := C.OGR_F_GetFieldAsDateTime(
 		feature.cval,
@@ -200,16 +454,37 @@ func (feature Feature) FieldAsDateTime(index int) (time.Time, bool) {
 
 // Set field to integer value
 func (feature Feature) SetFieldInteger(index, value int) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	C.OGR_F_SetFieldInteger(feature.cval, C.int(index), C.int(value))
 }
 
+// Set field to 64 bit integer value
+func (feature Feature) SetFieldInteger64(index int, value int64) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	C.OGR_F_SetFieldInteger64(feature.cval, C.int(index), C.GIntBig(value))
+}
+
 // Set field to float64 value
 func (feature Feature) SetFieldFloat64(index int, value float64) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	C.OGR_F_SetFieldDouble(feature.cval, C.int(index), C.double(value))
 }
 
 // Set field to string value
 func (feature Feature) SetFieldString(index int, value string) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	cVal := C.CString(value)
 	defer C.free(unsafe.Pointer(cVal))
 	C.OGR_F_SetFieldString(feature.cval, C.int(index), cVal)
@@ -217,16 +492,58 @@ func (feature Feature) SetFieldString(index int, value string) {
 
 // Set field to list of integers
 func (feature Feature) SetFieldIntegerList(index int, value []int) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	if len(value) == 0 {
+		C.OGR_F_SetFieldIntegerList(feature.cval, C.int(index), 0, nil)
+		return
+	}
+	cValue := make([]C.int, len(value))
+	for i, v := range value {
+		cValue[i] = C.int(v)
+	}
 	C.OGR_F_SetFieldIntegerList(
 		feature.cval,
 		C.int(index),
 		C.int(len(value)),
-		(*C.int)(unsafe.Pointer(&value[0])),
+		&cValue[0],
+	)
+}
+
+// Set field to list of 64 bit integers
+func (feature Feature) SetFieldInteger64List(index int, value []int64) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	if len(value) == 0 {
+		C.OGR_F_SetFieldInteger64List(feature.cval, C.int(index), 0, nil)
+		return
+	}
+	cValue := make([]C.GIntBig, len(value))
+	for i, v := range value {
+		cValue[i] = C.GIntBig(v)
+	}
+	C.OGR_F_SetFieldInteger64List(
+		feature.cval,
+		C.int(index),
+		C.int(len(value)),
+		&cValue[0],
 	)
 }
 
 // Set field to list of float64
 func (feature Feature) SetFieldFloat64List(index int, value []float64) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	if len(value) == 0 {
+		C.OGR_F_SetFieldDoubleList(feature.cval, C.int(index), 0, nil)
+		return
+	}
 	C.OGR_F_SetFieldDoubleList(
 		feature.cval,
 		C.int(index),
@@ -237,6 +554,10 @@ func (feature Feature) SetFieldFloat64List(index int, value []float64) {
 
 // Set field to list of strings
 func (feature Feature) SetFieldStringList(index int, value []string) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	length := len(value)
 	cValue := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -254,11 +575,23 @@ func (feature Feature) SetFieldStringList(index int, value []string) {
 
 // Set field from the raw field pointer
 func (feature Feature) SetFieldRaw(index int, field Field) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	C.OGR_F_SetFieldRaw(feature.cval, C.int(index), field.cval)
 }
 
 // Set field as binary data
 func (feature Feature) SetFieldBinary(index int, value []uint8) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
+	if len(value) == 0 {
+		C.OGR_F_SetFieldBinary(feature.cval, C.int(index), 0, nil)
+		return
+	}
 	C.OGR_F_SetFieldBinary(
 		feature.cval,
 		C.int(index),
@@ -269,6 +602,10 @@ func (feature Feature) SetFieldBinary(index int, value []uint8) {
 
 // Set field as date / time
 func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	C.OGR_F_SetFieldDateTime(
 		feature.cval,
 		C.int(index),
@@ -283,13 +620,22 @@ func (feature Feature) SetFieldDateTime(index int, dt time.Time) {
 }
 
 // Fetch feature indentifier
-func (feature Feature) FID() int {
+func (feature Feature) FID() int64 {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return 0
+	}
 	fid := C.OGR_F_GetFID(feature.cval)
-	return int(fid)
+	return int64(fid)
 }
 
 // Set feature identifier
-func (feature Feature) SetFID(fid int) error {
+func (feature Feature) SetFID(fid int64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return ErrClosed
+	}
 	return C.OGR_F_SetFID(feature.cval, C.GIntBig(fid)).Err()
 }
 
@@ -297,11 +643,23 @@ func (feature Feature) SetFID(fid int) error {
 
 // Set one feature from another
 func (this Feature) SetFrom(other Feature, forgiving int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(this)
+	defer runtime.KeepAlive(other)
+	if this.closed() {
+		return ErrClosed
+	}
 	return C.OGR_F_SetFrom(this.cval, other.cval, C.int(forgiving)).Err()
 }
 
 // Set one feature from another, using field map
 func (this Feature) SetFromWithMap(other Feature, forgiving int, fieldMap []int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(this)
+	defer runtime.KeepAlive(other)
+	if this.closed() {
+		return ErrClosed
+	}
 	return C.OGR_F_SetFromWithMap(
 		this.cval,
 		other.cval,
@@ -312,12 +670,20 @@ func (this Feature) SetFromWithMap(other Feature, forgiving int, fieldMap []int)
 
 // Fetch style string for this feature
 func (feature Feature) StlyeString() string {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return ""
+	}
 	style := C.OGR_F_GetStyleString(feature.cval)
 	return C.GoString(style)
 }
 
 // Set style string for this feature
 func (feature Feature) SetStyleString(style string) {
+	defer runtime.KeepAlive(feature)
+	if feature.closed() {
+		return
+	}
 	cStyle := C.CString(style)
 	C.OGR_F_SetStyleStringDirectly(feature.cval, cStyle)
 }
@@ -13,6 +13,9 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"runtime"
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -38,37 +41,26 @@ var (
 	ErrIllegal = errors.New("Illegal Error")
 )
 
-// Error handling.  The following is bare-bones, and needs to be replaced with something more useful.
+// Err converts a CPLErr return code into an error.  A CE_None code yields nil,
+// anything else an *Error carrying the message GDAL recorded for the call,
+// which must be made within an errorScope().
 func (err _Ctype_CPLErr) Err() error {
-	switch err {
-	case 0:
+	if err == C.CE_None {
 		return nil
-	case 1:
-		return ErrDebug
-	case 2:
-		return ErrWarning
-	case 3:
-		return ErrFailure
-	case 4:
-		return ErrFailure
 	}
-	return ErrIllegal
+	return lastError(ErrorClass(err))
 }
 
+// Err converts an OGRErr return code into an error.  OGRERR_NONE yields nil,
+// anything else an *Error carrying the message GDAL recorded for the call,
+// which must be made within an errorScope().
 func (err _Ctype_OGRErr) Err() error {
-	switch err {
-	case 0:
+	if err == C.OGRERR_NONE {
 		return nil
-	case 1:
-		return ErrDebug
-	case 2:
-		return ErrWarning
-	case 3:
-		return ErrFailure
-	case 4:
-		return ErrFailure
 	}
-	return ErrIllegal
+	e := lastError(CE_Failure)
+	e.ogrErr = int(err)
+	return e
 }
 
 // Pixel data types
@@ -87,6 +79,12 @@ const (
 	CInt32   = DataType(C.GDT_CInt32)
 	CFloat32 = DataType(C.GDT_CFloat32)
 	CFloat64 = DataType(C.GDT_CFloat64)
+
+	// Int8 requires GDAL 3.7, Int64 and UInt64 GDAL 3.5.  They equal Unknown
+	// with older versions.
+	Int8   = DataType(C.GO_GDT_INT8)
+	Int64  = DataType(C.GO_GDT_INT64)
+	UInt64 = DataType(C.GO_GDT_UINT64)
 )
 
 // Get data type size in bits.
@@ -267,11 +265,14 @@ type MajorObject struct {
 }
 
 type Dataset struct {
-	cval C.GDALDatasetH
+	cval   C.GDALDatasetH
+	life   *lifetime
+	parent *lifetime
 }
 
 type RasterBand struct {
-	cval C.GDALRasterBandH
+	cval   C.GDALRasterBandH
+	parent *lifetime
 }
 
 type Driver struct {
@@ -279,11 +280,15 @@ type Driver struct {
 }
 
 type ColorTable struct {
-	cval C.GDALColorTableH
+	cval   C.GDALColorTableH
+	life   *lifetime
+	parent *lifetime
 }
 
 type RasterAttributeTable struct {
-	cval C.GDALRasterAttributeTableH
+	cval   C.GDALRasterAttributeTableH
+	life   *lifetime
+	parent *lifetime
 }
 
 type AsyncReader struct {
@@ -338,9 +343,29 @@ type goGDALProgressFuncProxyArgs struct {
 	data          interface{}
 }
 
+// progressProxy returns the C progress function and argument forwarding the
+// progress reports of GDAL to progress.  A nil progress maps to no progress
+// reporting.  The returned function releases the argument, and must be called
+// once GDAL no longer uses it.
+func progressProxy(progress ProgressFunc, data interface{}) (C.GDALProgressFunc, unsafe.Pointer, func()) {
+	if progress == nil {
+		return nil, nil, func() {}
+	}
+	// The argument is handed to C as a handle stored in C memory, as Go
+	// memory holding Go pointers must not be passed to C.
+	h := cgo.NewHandle(&goGDALProgressFuncProxyArgs{progress, data})
+	arg := C.malloc(C.size_t(unsafe.Sizeof(C.uintptr_t(0))))
+	*(*C.uintptr_t)(arg) = C.uintptr_t(h)
+	return C.goGDALProgressFuncProxyB(), arg, func() {
+		h.Delete()
+		C.free(arg)
+	}
+}
+
 //export goGDALProgressFuncProxyA
 func goGDALProgressFuncProxyA(complete C.double, message *C.char, data unsafe.Pointer) int {
-	arg := (*goGDALProgressFuncProxyArgs)(data)
+	h := cgo.Handle(*(*C.uintptr_t)(data))
+	arg := h.Value().(*goGDALProgressFuncProxyArgs)
 	return arg.progresssFunc(
 		float64(complete), C.GoString(message), arg.data,
 	)
@@ -371,16 +396,19 @@ func Open(filename string, access Access) (*Dataset, error) {
 	if dataset == nil {
 		return nil, fmt.Errorf("Error: dataset '%s' open error", filename)
 	}
-	return &Dataset{dataset}, nil
+	return newDataset(dataset), nil
 }
 
 // Open a shared existing dataset
-func OpenShared(filename string, access Access) Dataset {
+func OpenShared(filename string, access Access) (*Dataset, error) {
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 
 	dataset := C.GDALOpenShared(cFilename, C.GDALAccess(access))
-	return Dataset{dataset}
+	if dataset == nil {
+		return nil, fmt.Errorf("Error: dataset '%s' open error", filename)
+	}
+	return newDataset(dataset), nil
 }
 
 // TODO(kyle): deprecate Open(), rename OpenEx->Open
@@ -434,7 +462,7 @@ func OpenEx(filename string, flags Access, allowedDrivers []string, options []st
 	if dataset == nil {
 		return nil, fmt.Errorf("Error: dataset '%s' open error", filename)
 	}
-	return &Dataset{dataset}, nil
+	return newDataset(dataset), nil
 }
 
 // Unimplemented: DumpOpenDatasets
@@ -442,6 +470,41 @@ func OpenEx(filename string, flags Access, allowedDrivers []string, options []st
 /*      GDAL_GCP                                                        */
 /* ==================================================================== */
 
+// Ground control point, tying a pixel/line location of a raster to a
+// georeferenced location
+type GCP struct {
+	ID    string
+	Info  string
+	Pixel float64
+	Line  float64
+	X     float64
+	Y     float64
+	Z     float64
+}
+
+// cGCPs converts the GCPs to their C form.  The returned function frees the C
+// strings.
+func cGCPs(gcps []GCP) ([]C.GDAL_GCP, func()) {
+	cList := make([]C.GDAL_GCP, len(gcps))
+	for i, gcp := range gcps {
+		cList[i] = C.GDAL_GCP{
+			pszId:      C.CString(gcp.ID),
+			pszInfo:    C.CString(gcp.Info),
+			dfGCPPixel: C.double(gcp.Pixel),
+			dfGCPLine:  C.double(gcp.Line),
+			dfGCPX:     C.double(gcp.X),
+			dfGCPY:     C.double(gcp.Y),
+			dfGCPZ:     C.double(gcp.Z),
+		}
+	}
+	return cList, func() {
+		for i := range cList {
+			C.free(unsafe.Pointer(cList[i].pszId))
+			C.free(unsafe.Pointer(cList[i].pszInfo))
+		}
+	}
+}
+
 // Unimplemented: InitGCPs
 // Unimplemented: DeinitGCPs
 // Unimplemented: DuplicateGCPs
@@ -454,6 +517,9 @@ func OpenEx(filename string, flags Access, allowedDrivers []string, options []st
 
 // Fetch object description
 func (object MajorObject) Description() string {
+	if object.closed() {
+		return ""
+	}
 	cObject := object.cval
 	desc := C.GoString(C.GDALGetDescription(cObject))
 	return desc
@@ -461,6 +527,9 @@ func (object MajorObject) Description() string {
 
 // Set object description
 func (object MajorObject) SetDescription(desc string) {
+	if object.closed() {
+		return
+	}
 	cObject := object.cval
 	cDesc := C.CString(desc)
 	defer C.free(unsafe.Pointer(cDesc))
@@ -469,29 +538,45 @@ func (object MajorObject) SetDescription(desc string) {
 
 // Fetch metadata
 func (object MajorObject) Metadata(domain string) []string {
+	if object.closed() {
+		return nil
+	}
 	panic("not implemented!")
 	return nil
 }
 
 // Set metadata
 func (object MajorObject) SetMetadata(metadata []string, domain string) {
+	if object.closed() {
+		return
+	}
 	panic("not implemented!")
 	return
 }
 
 // Fetch a single metadata item
 func (object MajorObject) MetadataItem(name, domain string) string {
+	if object.closed() {
+		return ""
+	}
 	panic("not implemented!")
 	return ""
 }
 
 // Set a single metadata item
 func (object MajorObject) SetMetadataItem(name, value, domain string) {
+	if object.closed() {
+		return
+	}
 	panic("not implemented!")
 	return
 }
 
 func (dataset *Dataset) Metadata(domain string) []string {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil
+	}
 	c_domain := C.CString(domain)
 	defer C.free(unsafe.Pointer(c_domain))
 
@@ -512,6 +597,11 @@ func (dataset *Dataset) Metadata(domain string) []string {
 // TODO: Make korrekt class hirerarchy via interfaces
 
 func (object *RasterBand) SetMetadataItem(name, value, domain string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(object)
+	if object.closed() {
+		return ErrClosed
+	}
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -530,6 +620,11 @@ func (object *RasterBand) SetMetadataItem(name, value, domain string) error {
 // TODO: Make korrekt class hirerarchy via interfaces
 
 func (object *Dataset) SetMetadataItem(name, value, domain string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(object)
+	if object.closed() {
+		return ErrClosed
+	}
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -547,6 +642,9 @@ func (object *Dataset) SetMetadataItem(name, value, domain string) error {
 
 // Fetch single metadata item.
 func (object *Driver) MetadataItem(name, domain string) string {
+	if object.closed() {
+		return ""
+	}
 	c_name := C.CString(name)
 	defer C.free(unsafe.Pointer(c_name))
 
@@ -567,11 +665,19 @@ func (object *Driver) MetadataItem(name, domain string) string {
 
 // Get the driver to which this dataset relates
 func (dataset *Dataset) Driver() *Driver {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil
+	}
 	return &Driver{C.GDALGetDatasetDriver(dataset.cval)}
 }
 
 // Fetch files forming the dataset.
 func (dataset *Dataset) FileList() []string {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil
+	}
 	p := C.GDALGetFileList(dataset.cval)
 	var strings []string
 	q := uintptr(unsafe.Pointer(p))
@@ -586,24 +692,60 @@ func (dataset *Dataset) FileList() []string {
 	return strings
 }
 
-// Close the dataset
-func (dataset *Dataset) Close() {
-	C.GDALClose(dataset.cval)
-	return
+// newDataset returns a dataset owned by the caller
+func newDataset(h C.GDALDatasetH) *Dataset {
+	return &Dataset{
+		cval: h,
+		life: newLifetime(func() { C.GDALClose(h) }),
+	}
+}
+
+// owner returns the lifetime of the dataset, or of its parent if borrowed
+func (dataset *Dataset) owner() *lifetime {
+	if dataset.life != nil {
+		return dataset.life
+	}
+	return dataset.parent
+}
+
+// Close the dataset, flushing its pending writes.  Closing a dataset that is
+// already closed does nothing.
+func (dataset *Dataset) Close() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	var err error
+	if dataset.cval != nil && dataset.life.owned() && dataset.life.release() {
+		err = C.goGDALClose(dataset.cval).Err()
+		dataset.life.runCleanups()
+	}
+	dataset.cval = nil
+	return err
 }
 
 // Fetch X size of raster
 func (dataset *Dataset) RasterXSize() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	return int(C.GDALGetRasterXSize(dataset.cval))
 }
 
 // Fetch Y size of raster
 func (dataset *Dataset) RasterYSize() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	return int(C.GDALGetRasterYSize(dataset.cval))
 }
 
 // Fetch the number of raster bands in the dataset
 func (dataset *Dataset) RasterCount() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	return int(C.GDALGetRasterCount(dataset.cval))
 }
 
@@ -617,15 +759,24 @@ var ErrIllegalBand = errors.New("illegal band #")
 //
 // If the band is invalid, nil and ErrInvalidBand is returned
 func (dataset *Dataset) RasterBand(band int) (*RasterBand, error) {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil, ErrClosed
+	}
 	p := C.GDALGetRasterBand(dataset.cval, C.int(band))
 	if p == nil {
 		return nil, ErrIllegalBand
 	}
-	return &RasterBand{p}, nil
+	return &RasterBand{cval: p, parent: dataset.owner()}, nil
 }
 
 // Add a band to a dataset
 func (dataset *Dataset) AddBand(dataType DataType, options []string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -649,9 +800,35 @@ const (
 	GRA_Cubic            = ResampleAlg(2)
 	GRA_CubicSpline      = ResampleAlg(3)
 	GRA_Lanczos          = ResampleAlg(4)
+	GRA_Average          = ResampleAlg(5)
+	GRA_Mode             = ResampleAlg(6)
 )
 
+// name returns the name of the resampling algorithm, as understood by the -r
+// switch of the GDAL utilities.
+func (alg ResampleAlg) name() string {
+	switch alg {
+	case GRA_Bilinear:
+		return "bilinear"
+	case GRA_Cubic:
+		return "cubic"
+	case GRA_CubicSpline:
+		return "cubicspline"
+	case GRA_Lanczos:
+		return "lanczos"
+	case GRA_Average:
+		return "average"
+	case GRA_Mode:
+		return "mode"
+	}
+	return "near"
+}
+
 func (dataset *Dataset) AutoCreateWarpedVRT(srcWKT, dstWKT string, resampleAlg ResampleAlg) (*Dataset, error) {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil, ErrClosed
+	}
 	c_srcWKT := C.CString(srcWKT)
 	defer C.free(unsafe.Pointer(c_srcWKT))
 	c_dstWKT := C.CString(dstWKT)
@@ -660,7 +837,7 @@ func (dataset *Dataset) AutoCreateWarpedVRT(srcWKT, dstWKT string, resampleAlg R
 	if h == nil {
 		return nil, fmt.Errorf("AutoCreateWarpedVRT failed")
 	}
-	return &Dataset{h}, nil
+	return newDataset(h), nil
 }
 
 // Unimplemented: GDALBeginAsyncReader
@@ -676,11 +853,16 @@ func (dataset *Dataset) IO(
 	bandMap []int,
 	pixelSpace, lineSpace, bandSpace int,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	var dataType DataType
 	var dataPtr unsafe.Pointer
 	switch data := buffer.(type) {
 	case []int8:
-		dataType = Byte
+		dataType = Int8
 		dataPtr = unsafe.Pointer(&data[0])
 	case []uint8:
 		dataType = Byte
@@ -697,15 +879,30 @@ func (dataset *Dataset) IO(
 	case []uint32:
 		dataType = UInt32
 		dataPtr = unsafe.Pointer(&data[0])
+	case []int64:
+		dataType = Int64
+		dataPtr = unsafe.Pointer(&data[0])
+	case []uint64:
+		dataType = UInt64
+		dataPtr = unsafe.Pointer(&data[0])
 	case []float32:
 		dataType = Float32
 		dataPtr = unsafe.Pointer(&data[0])
 	case []float64:
 		dataType = Float64
 		dataPtr = unsafe.Pointer(&data[0])
+	case []complex64:
+		dataType = CFloat32
+		dataPtr = unsafe.Pointer(&data[0])
+	case []complex128:
+		dataType = CFloat64
+		dataPtr = unsafe.Pointer(&data[0])
 	default:
 		return fmt.Errorf("Error: buffer is not a valid data type (must be a valid numeric slice)")
 	}
+	if dataType == Unknown {
+		return fmt.Errorf("Error: %T buffers are not supported by GDAL %d.%d", buffer, VERSION_MAJOR, VERSION_MINOR)
+	}
 
 	return C.GDALDatasetRasterIO(
 		dataset.cval,
@@ -729,6 +926,11 @@ func (dataset *Dataset) AdviseRead(
 	bandMap []int,
 	options []string,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -750,11 +952,20 @@ func (dataset *Dataset) AdviseRead(
 
 // Fetch the projection definition string for this dataset
 func (dataset *Dataset) ProjectionRef() string {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ""
+	}
 	return C.GoString(C.GDALGetProjectionRef(dataset.cval))
 }
 
 // Set the projection reference string
 func (dataset *Dataset) SetProjection(proj string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 
@@ -763,6 +974,10 @@ func (dataset *Dataset) SetProjection(proj string) error {
 
 // Get the affine transformation coefficients
 func (dataset *Dataset) GeoTransform() [6]float64 {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return [6]float64{}
+	}
 	var transform [6]float64
 	C.GDALGetGeoTransform(dataset.cval, (*C.double)(unsafe.Pointer(&transform[0])))
 	return transform
@@ -770,6 +985,11 @@ func (dataset *Dataset) GeoTransform() [6]float64 {
 
 // Set the affine transformation coefficients
 func (dataset *Dataset) SetGeoTransform(transform [6]float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	return C.GDALSetGeoTransform(
 		dataset.cval,
 		(*C.double)(unsafe.Pointer(&transform[0])),
@@ -778,6 +998,9 @@ func (dataset *Dataset) SetGeoTransform(transform [6]float64) error {
 
 // Return the inverted transform
 func (dataset *Dataset) InvGeoTransform() [6]float64 {
+	if dataset.closed() {
+		return [6]float64{}
+	}
 	return InvGeoTransform(dataset.GeoTransform())
 }
 
@@ -790,6 +1013,10 @@ func InvGeoTransform(transform [6]float64) [6]float64 {
 
 // Get number of GCPs
 func (dataset *Dataset) GDALGetGCPCount() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	return int(C.GDALGetGCPCount(dataset.cval))
 }
 
@@ -799,6 +1026,10 @@ func (dataset *Dataset) GDALGetGCPCount() int {
 
 // Fetch a format specific internally meaningful handle
 func (dataset *Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return nil
+	}
 	cRequest := C.CString(request)
 	defer C.free(unsafe.Pointer(cRequest))
 
@@ -808,12 +1039,20 @@ func (dataset *Dataset) GDALGetInternalHandle(request string) unsafe.Pointer {
 
 // Add one to dataset reference count
 func (dataset *Dataset) GDALReferenceDataset() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	count := C.GDALReferenceDataset(dataset.cval)
 	return int(count)
 }
 
 // Subtract one from dataset reference count
 func (dataset *Dataset) GDALDereferenceDataset() int {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	count := C.GDALDereferenceDataset(dataset.cval)
 	return int(count)
 }
@@ -828,10 +1067,16 @@ func (dataset *Dataset) BuildOverviews(
 	progress ProgressFunc,
 	data interface{},
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	cResampling := C.CString(resampling)
 	defer C.free(unsafe.Pointer(cResampling))
 
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	return C.GDALBuildOverviews(
 		dataset.cval,
@@ -840,8 +1085,8 @@ func (dataset *Dataset) BuildOverviews(
 		(*C.int)(unsafe.Pointer(&IntSliceToCInt(overviewList)[0])),
 		C.int(nBands),
 		(*C.int)(unsafe.Pointer(&IntSliceToCInt(bandList)[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
@@ -849,18 +1094,31 @@ func (dataset *Dataset) BuildOverviews(
 
 // Return access flag
 func (dataset *Dataset) Access() Access {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return 0
+	}
 	accessVal := C.GDALGetAccess(dataset.cval)
 	return Access(accessVal)
 }
 
 // Write all write cached data to disk
 func (dataset *Dataset) FlushCache() {
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return
+	}
 	C.GDALFlushCache(dataset.cval)
 	return
 }
 
 // Adds a mask band to the dataset
 func (dataset *Dataset) CreateMaskBand(flags int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(dataset)
+	if dataset.closed() {
+		return ErrClosed
+	}
 	return C.GDALCreateDatasetMaskBand(dataset.cval, C.int(flags)).Err()
 }
 
@@ -871,7 +1129,14 @@ func (sourceDataset Dataset) CopyWholeRaster(
 	progress ProgressFunc,
 	data interface{},
 ) error {
-	arg := &goGDALProgressFuncProxyArgs{progress, data}
+	defer errorScope()()
+	defer runtime.KeepAlive(sourceDataset)
+	defer runtime.KeepAlive(destDataset)
+	if sourceDataset.closed() {
+		return ErrClosed
+	}
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
 
 	length := len(options)
 	cOptions := make([]*C.char, length+1)
@@ -885,13 +1150,17 @@ func (sourceDataset Dataset) CopyWholeRaster(
 		sourceDataset.cval,
 		destDataset.cval,
 		(**C.char)(unsafe.Pointer(&cOptions[0])),
-		C.goGDALProgressFuncProxyB(),
-		unsafe.Pointer(arg),
+		cProgress,
+		cArg,
 	).Err()
 }
 
 // LayerCount gets the number of layers in this dataset.
-func (ds Dataset) LayerCount() int {
+func (ds *Dataset) LayerCount() int {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return 0
+	}
 	return int(C.GDALDatasetGetLayerCount(ds.cval))
 }
 
@@ -901,12 +1170,31 @@ func (ds Dataset) LayerCount() int {
 // by the application.
 //
 // This function is the same as the C++ method GDALDataset::GetLayer()
-func (ds Dataset) Layer(layer int) (Layer, error) {
+func (ds *Dataset) Layer(layer int) (Layer, error) {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return Layer{}, ErrClosed
+	}
 	lyr := C.GDALDatasetGetLayer(ds.cval, C.int(layer))
 	if lyr == nil {
-		return Layer{lyr}, fmt.Errorf("failed to get layer")
+		return Layer{}, fmt.Errorf("failed to get layer")
+	}
+	return Layer{cval: lyr, parent: ds.owner()}, nil
+}
+
+// Layers returns an iterator over the layers of the dataset
+func (ds *Dataset) Layers() iter.Seq[Layer] {
+	return func(yield func(Layer) bool) {
+		for i := 0; i < ds.LayerCount(); i++ {
+			layer, err := ds.Layer(i)
+			if err != nil {
+				return
+			}
+			if !yield(layer) {
+				return
+			}
+		}
 	}
-	return Layer{lyr}, nil
 }
 
 // LayerByName fetches a layer by name.
@@ -915,13 +1203,50 @@ func (ds Dataset) Layer(layer int) (Layer, error) {
 // by the application.
 //
 // This function is the same as the C++ method GDALDataset::GetLayerByName()
-func (ds Dataset) LayerByName(name string) (*Layer, error) {
+func (ds *Dataset) LayerByName(name string) (*Layer, error) {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return nil, ErrClosed
+	}
 	cName := C.CString(name)
 	lyr := C.GDALDatasetGetLayerByName(ds.cval, cName)
 	if lyr == nil {
 		return nil, fmt.Errorf("failed to get layer")
 	}
-	return &Layer{lyr}, nil
+	return &Layer{cval: lyr, parent: ds.owner()}, nil
+}
+
+// CreateLayer creates a new layer in a vector dataset.
+//
+// This function is the same as the C++ method GDALDataset::CreateLayer()
+func (ds *Dataset) CreateLayer(
+	name string,
+	sr SpatialReference,
+	geomType GeometryType,
+	options []string,
+) (Layer, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(sr)
+	if ds.closed() {
+		return Layer{}, ErrClosed
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cOpts, free := cStringList(options)
+	defer free()
+
+	lyr := C.GDALDatasetCreateLayer(
+		ds.cval,
+		cName,
+		sr.cval,
+		C.OGRwkbGeometryType(geomType),
+		(**C.char)(unsafe.Pointer(&cOpts[0])),
+	)
+	if lyr == nil {
+		return Layer{}, lastError(CE_Failure)
+	}
+	return Layer{cval: lyr, parent: ds.owner()}, nil
 }
 
 // ExecuteSQL Executes an SQL statement against the data store.
@@ -937,7 +1262,12 @@ func (ds Dataset) LayerByName(name string) (*Layer, error) {
 // For more information on the SQL dialect supported internally by OGR review
 // the OGR SQL document. Some drivers (i.e. Oracle and PostGIS) pass the SQL
 // directly through to the underlying RDBMS.
-func (ds Dataset) ExecuteSQL(sql string, spatialFilter Geometry, dialect string) (*Layer, error) {
+func (ds *Dataset) ExecuteSQL(sql string, spatialFilter Geometry, dialect string) (*Layer, error) {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(spatialFilter)
+	if ds.closed() {
+		return nil, ErrClosed
+	}
 	cSQL := C.CString(sql)
 	var cDialect *C.char
 	if dialect == "" {
@@ -949,7 +1279,7 @@ func (ds Dataset) ExecuteSQL(sql string, spatialFilter Geometry, dialect string)
 	if lyr == nil {
 		return nil, fmt.Errorf("failed to execute SQL")
 	}
-	return &Layer{lyr}, nil
+	return &Layer{cval: lyr, parent: ds.owner()}, nil
 }
 
 // Generate downsampled overviews
@@ -5,7 +5,21 @@
 package gdal
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 func TestTiffDriver(t *testing.T) {
@@ -178,6 +192,1694 @@ func TestHistogram(t *testing.T) {
 	}
 }
 
+func TestReadWriteWindow(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 4, 3, 2, Float32, nil)
+	defer ds.Close()
+	band, err := ds.RasterBand(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]float64, 4*3)
+	for i := range data {
+		data[i] = float64(i) + 0.5
+	}
+	win := Window{XSize: 4, YSize: 3}
+	if err := WriteWindow(band, win, data); err != nil {
+		t.Fatal(err)
+	}
+	f32, err := ReadWindow[float32](band, Window{XOff: 1, YOff: 1, XSize: 2, YSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float32{5.5, 6.5, 9.5, 10.5} {
+		if f32[i] != want {
+			t.Errorf("pixel %d: got %v, expected %v", i, f32[i], want)
+		}
+	}
+	if err := WriteWindow(band, win, data[:5]); err == nil {
+		t.Error("wrote a short buffer")
+	}
+	if _, err := ReadWindow[uint8](band, Window{XSize: -1, YSize: 1}); err != ErrInvalidWindow {
+		t.Errorf("invalid window not rejected: %v", err)
+	}
+}
+
+func TestReadWriteWindowInt8(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 2, 2, 1, Int16, nil)
+	defer ds.Close()
+	band, err := ds.RasterBand(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	win := Window{XSize: 2, YSize: 2}
+	in := []int8{-128, -1, 0, 127}
+	if Int8 == Unknown {
+		if err := WriteWindow(band, win, in); err == nil {
+			t.Error("int8 buffer accepted without Int8 support")
+		}
+		return
+	}
+	if err := WriteWindow(band, win, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReadWindow[int16](band, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range in {
+		if int16(in[i]) != out[i] {
+			t.Errorf("pixel %d: got %v, expected %v", i, out[i], in[i])
+		}
+	}
+}
+
+func TestReadWriteWindowComplex(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 2, 2, 1, CInt16, nil)
+	defer ds.Close()
+	band, err := ds.RasterBand(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	win := Window{XSize: 2, YSize: 2}
+	in := []complex64{1 + 2i, -3 + 4i, 5 - 6i, 7}
+	if err := WriteWindow(band, win, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReadWindow[complex128](band, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range in {
+		if complex128(in[i]) != out[i] {
+			t.Errorf("pixel %d: got %v, expected %v", i, out[i], in[i])
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	drv, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fname := "/vsimem/blocks.tif"
+	ds := drv.Create(fname, 40, 40, 1, Byte,
+		[]string{"TILED=YES", "BLOCKXSIZE=16", "BLOCKYSIZE=16"})
+	defer drv.DeleteDataset(fname)
+	defer ds.Close()
+	band, err := ds.RasterBand(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, pixels := 0, 0
+	for blk := range band.Blocks() {
+		n++
+		pixels += blk.XSize * blk.YSize
+		if blk.X == 2 && blk.XSize != 8 {
+			t.Errorf("edge block not clipped: %+v", blk)
+		}
+	}
+	if n != 9 || pixels != 40*40 {
+		t.Errorf("got %d blocks covering %d pixels", n, pixels)
+	}
+}
+
+func TestProcessBlocks(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := drv.Create("", 20, 10, 1, Int16, nil)
+	defer src.Close()
+	dst := drv.Create("", 20, 10, 1, Int16, nil)
+	defer dst.Close()
+	in, _ := src.RasterBand(1)
+	out, _ := dst.RasterBand(1)
+	data := make([]int16, 20*10)
+	for i := range data {
+		data[i] = int16(i)
+	}
+	if err := WriteWindow(in, Window{XSize: 20, YSize: 10}, data); err != nil {
+		t.Fatal(err)
+	}
+	err = ProcessBlocks(context.Background(), []*RasterBand{in}, []*RasterBand{out}, 4,
+		func(blk Block, in, out [][]int16) error {
+			for i, v := range in[0] {
+				out[0][i] = 2 * v
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ReadWindow[int16](out, Window{XSize: 20, YSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range result {
+		if result[i] != 2*data[i] {
+			t.Fatalf("pixel %d: got %d, expected %d", i, result[i], 2*data[i])
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ProcessBlocks(ctx, []*RasterBand{in}, nil, 1,
+		func(blk Block, in, out [][]int16) error { return nil })
+	if err != context.Canceled {
+		t.Errorf("cancellation not reported: %v", err)
+	}
+}
+
+func countProgress(complete float64, message string, data interface{}) int {
+	*data.(*int)++
+	return 1
+}
+
+func TestWarp(t *testing.T) {
+	src, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	var calls int
+	ds, err := Warp("/vsimem/warped.tif", []*Dataset{src}, WarpOptions{
+		DstSRS:       "EPSG:3857",
+		Width:        100,
+		Height:       100,
+		ResampleAlg:  GRA_Bilinear,
+		Progress:     countProgress,
+		ProgressData: &calls,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIMemFile{"/vsimem/warped.tif"}.Unlink()
+	defer ds.Close()
+	if ds.RasterXSize() != 100 || ds.RasterYSize() != 100 {
+		t.Errorf("invalid size: %dx%d", ds.RasterXSize(), ds.RasterYSize())
+	}
+	sr := CreateSpatialReference(ds.ProjectionRef())
+	defer sr.Destroy()
+	if sr.AuthorityCode("") != "3857" {
+		t.Errorf("invalid projection: %s", ds.ProjectionRef())
+	}
+	if calls == 0 {
+		t.Error("progress not reported")
+	}
+}
+
+func TestWarpInto(t *testing.T) {
+	src, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := drv.Create("", 40, 20, 1, Byte, nil)
+	defer dst.Close()
+	dst.SetProjection(src.ProjectionRef())
+	dst.SetGeoTransform([6]float64{-180, 9, 0, 90, 0, -9})
+	if err := dst.WarpInto([]*Dataset{src}, WarpOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	band, _ := dst.RasterBand(1)
+	if band.Checksum(0, 0, 40, 20) == 0 {
+		t.Error("nothing warped into dataset")
+	}
+}
+
+func TestTranslateInfo(t *testing.T) {
+	src, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	calls := 0
+	dst, err := Translate("", src, []string{"-of", "MEM", "-outsize", "50%", "50%"}, countProgress, &calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	if dst.RasterXSize() != src.RasterXSize()/2 || dst.RasterYSize() != src.RasterYSize()/2 {
+		t.Errorf("got size %dx%d", dst.RasterXSize(), dst.RasterYSize())
+	}
+	if calls == 0 {
+		t.Error("progress not reported")
+	}
+
+	info, err := Info(dst, []string{"-json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(info, `"MEM"`) {
+		t.Errorf("unexpected info: %s", info)
+	}
+
+	PushQuietHandler()
+	_, err = Translate("", src, []string{"-bogus"}, nil, nil)
+	PopHandler()
+	if err == nil {
+		t.Error("invalid option accepted")
+	}
+}
+
+func TestBuildVRT(t *testing.T) {
+	vrt, err := BuildVRT("/vsimem/test.vrt", nil, []string{"test/small_world.tif"}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIMemFile{"/vsimem/test.vrt"}.Unlink()
+	defer vrt.Close()
+	if vrt.RasterCount() != 3 {
+		t.Errorf("got %d bands, want 3", vrt.RasterCount())
+	}
+	if _, err := BuildVRT("/vsimem/test.vrt", nil, nil, nil, nil, nil); err == nil {
+		t.Error("no error without sources")
+	}
+}
+
+func TestDEMProcessing(t *testing.T) {
+	src, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := DEMProcessing("", src, "hillshade", "", []string{"-of", "MEM"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	if dst.RasterXSize() != src.RasterXSize() {
+		t.Errorf("got width %d, want %d", dst.RasterXSize(), src.RasterXSize())
+	}
+}
+
+func TestVectorTranslate(t *testing.T) {
+	src, err := OpenEx("test/poly.shp", VectorDrivers, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := VectorTranslate("/vsimem/poly.json", src, []string{"-f", "GeoJSON"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIMemFile{"/vsimem/poly.json"}.Unlink()
+	defer dst.Close()
+	info, err := VectorInfo(dst, []string{"-so", "-al"})
+	if VERSION_NUM < 3070000 {
+		if err == nil {
+			t.Error("VectorInfo succeeded before GDAL 3.7")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(info, "Feature Count: 10") {
+		t.Errorf("unexpected info: %s", info)
+	}
+}
+
+func TestRasterizeGeometries(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 10, 10, 2, Byte, nil)
+	defer ds.Close()
+	ds.SetGeoTransform([6]float64{0, 1, 0, 10, 0, -1})
+
+	square, err := CreateFromWKT("POLYGON((0 10,5 10,5 5,0 5,0 10))", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer square.Destroy()
+	line, err := CreateFromWKT("LINESTRING(0 0.5,10 0.5)", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer line.Destroy()
+
+	err = ds.RasterizeGeometries([]int{1, 2}, []Geometry{square, line}, []float64{1, 2, 3, 4}, []string{"ALL_TOUCHED=TRUE"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	band, _ := ds.RasterBand(2)
+	data, err := ReadWindow[uint8](band, Window{XSize: 10, YSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != 2 || data[4*10+4] != 2 || data[7*10+7] != 0 || data[9*10+9] != 4 {
+		t.Errorf("unexpected burnt values: %v", data)
+	}
+
+	if err := ds.RasterizeGeometries([]int{1}, []Geometry{square}, []float64{1, 2, 3}, nil, nil, nil); err == nil {
+		t.Error("no error with mismatched burn values")
+	}
+}
+
+// xRamp returns a width by height surface whose values are the column index
+func xRamp(width, height int) []float64 {
+	data := make([]float64, width*height)
+	for i := range data {
+		data[i] = float64(i % width)
+	}
+	return data
+}
+
+func TestContourGenerate(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 10, 10, 1, Float64, nil)
+	defer ds.Close()
+	band, _ := ds.RasterBand(1)
+	if err := WriteWindow(band, Window{XSize: 10, YSize: 10}, xRamp(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	vds, ok := OGRDriverByName("Memory").Create("contours", nil)
+	if !ok {
+		t.Fatal("failed to create memory datasource")
+	}
+	defer vds.Destroy()
+	layer, err := vds.CreateLayer("contours", SpatialReference{}, GT_LineString, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer.CreateField(CreateFieldDefinition("ID", FT_Integer), false)
+	layer.CreateField(CreateFieldDefinition("ELEV", FT_Real), false)
+
+	err = band.ContourGenerate(2.5, 0, nil, false, 0, layer, 0, 1, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := layer.FeatureCount(true); n != 3 {
+		t.Errorf("got %d contours, want 3", n)
+	}
+
+	layer2, err := vds.CreateLayer("fixed", SpatialReference{}, GT_LineString, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = band.ContourGenerate(0, 0, []float64{4.5}, false, 0, layer2, -1, -1, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := layer2.FeatureCount(true); n != 1 {
+		t.Errorf("got %d contours, want 1", n)
+	}
+}
+
+func TestContourGenerator(t *testing.T) {
+	levels := make(map[float64]int)
+	cg, err := CreateContourGenerator(10, 10, false, 0, 2.5, 0, func(level float64, xs, ys []float64) error {
+		if len(xs) != len(ys) || len(xs) < 2 {
+			t.Errorf("invalid line of %d/%d vertices", len(xs), len(ys))
+		}
+		levels[level]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ramp := xRamp(10, 10)
+	for y := 0; y < 10; y++ {
+		if err := cg.FeedLine(ramp[y*10 : (y+1)*10]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 3 || levels[2.5] == 0 || levels[5] == 0 || levels[7.5] == 0 {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+
+	errStop := errors.New("stop")
+	cg, err = CreateContourGenerator(10, 10, false, 0, 2.5, 0, func(float64, []float64, []float64) error {
+		return errStop
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cg.FeedLine(ramp[:5]); err == nil {
+		t.Error("short scanline accepted")
+	}
+	for y := 0; y < 10 && err == nil; y++ {
+		err = cg.FeedLine(ramp[y*10 : (y+1)*10])
+	}
+	// lines left open by the last scanline are only written on close
+	if closeErr := cg.Close(); err == nil {
+		err = closeErr
+	}
+	if err != errStop {
+		t.Errorf("got error %v, want %v", err, errStop)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	// 4x4 grid over [0,4]x[0,4], pixel centers at 0.5, 1.5, 2.5 and 3.5
+	grid := func(opts GridOptions, x, y, z []float64) []float64 {
+		t.Helper()
+		buf := make([]float64, 16)
+		if err := Grid(opts, x, y, z, 0, 4, 0, 4, 4, 4, buf, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		return buf
+	}
+	near := func(got, want float64) bool {
+		return got-want < 1e-9 && want-got < 1e-9
+	}
+
+	x := []float64{1, 3, 1, 3}
+	y := []float64{1, 1, 3, 3}
+	z := []float64{1, 2, 3, 4}
+	buf := grid(NearestNeighborOptions{}, x, y, z)
+	want := []float64{
+		1, 1, 2, 2,
+		1, 1, 2, 2,
+		3, 3, 4, 4,
+		3, 3, 4, 4,
+	}
+	for i := range want {
+		if buf[i] != want[i] {
+			t.Fatalf("nearest neighbor: got %v, want %v", buf, want)
+		}
+	}
+
+	buf = grid(MovingAverageOptions{Radius1: 10, Radius2: 10}, x, y, z)
+	for i := range buf {
+		if !near(buf[i], 2.5) {
+			t.Fatalf("moving average: got %v, want 2.5", buf)
+		}
+	}
+
+	buf = grid(DataMetricsOptions{Metric: MetricCount, Radius1: 10, Radius2: 10}, x, y, z)
+	for i := range buf {
+		if buf[i] != 4 {
+			t.Fatalf("count: got %v, want 4", buf)
+		}
+	}
+
+	// two points on the centers of the first and last pixels of the first row
+	buf = grid(InverseDistanceOptions{Power: 2}, []float64{0.5, 3.5}, []float64{0.5, 0.5}, []float64{1, 3})
+	if !near(buf[0], 1) || !near(buf[3], 3) {
+		t.Errorf("inverse distance: got %v at the data points", buf[:4])
+	}
+	// weights 1/1 and 1/4 on the first row, 1/2 and 1/5 on the second one
+	if !near(buf[1], 1.75/1.25) || !near(buf[5], 1.1/0.7) {
+		t.Errorf("inverse distance: got %v and %v", buf[1], buf[5])
+	}
+
+	// z = x + 10y is reproduced exactly by the triangulation
+	x = []float64{0, 4, 0, 4}
+	y = []float64{0, 0, 4, 4}
+	z = []float64{0, 4, 40, 44}
+	buf = grid(LinearOptions{}, x, y, z)
+	for i := range buf {
+		px, py := float64(i%4)+0.5, float64(i/4)+0.5
+		if !near(buf[i], px+10*py) {
+			t.Fatalf("linear: got %v at pixel %d, want %v", buf[i], i, px+10*py)
+		}
+	}
+
+	if err := Grid(DataMetricsOptions{}, x, y, z, 0, 4, 0, 4, 4, 4, make([]float64, 16), nil, nil); err == nil {
+		t.Error("no error without a metric")
+	}
+	if err := Grid(LinearOptions{}, x, y, z, 0, 4, 0, 4, 4, 4, make([]float64, 15), nil, nil); err == nil {
+		t.Error("no error with a short buffer")
+	}
+	if err := Grid(LinearOptions{}, x, y, z, 0, 4, 0, 4, 0, 0, []float64{}, nil, nil); err == nil {
+		t.Error("no error with an empty buffer")
+	}
+	if VERSION_NUM >= 3070000 {
+		neg := []float64{-1, -2, -3, -4}
+		small := make([]int8, 16)
+		if err := Grid(NearestNeighborOptions{}, []float64{1, 3, 1, 3}, []float64{1, 1, 3, 3}, neg,
+			0, 4, 0, 4, 4, 4, small, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if small[0] != -1 || small[15] != -4 {
+			t.Errorf("int8: got %v", small)
+		}
+	}
+	calls := 0
+	if err := Grid(LinearOptions{}, x, y, z, 0, 4, 0, 4, 4, 4, make([]float32, 16), countProgress, &calls); err != nil {
+		t.Fatal(err)
+	}
+	if calls == 0 {
+		t.Error("progress not reported")
+	}
+}
+
+func TestGCPTransformer(t *testing.T) {
+	gcps := []GCP{
+		{ID: "1", Pixel: 0, Line: 0, X: 100, Y: 200},
+		{ID: "2", Pixel: 10, Line: 0, X: 110, Y: 200},
+		{ID: "3", Pixel: 0, Line: 10, X: 100, Y: 190},
+	}
+	tr, err := CreateGCPTransformer(gcps, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Destroy()
+
+	x, y := []float64{5}, []float64{5}
+	ok, err := tr.Transform(false, x, y, nil)
+	if err != nil || !ok[0] {
+		t.Fatal(err)
+	}
+	if x[0] < 104.999 || x[0] > 105.001 || y[0] < 194.999 || y[0] > 195.001 {
+		t.Errorf("got (%v, %v), want (105, 195)", x[0], y[0])
+	}
+	if _, err := tr.Transform(true, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if x[0] < 4.999 || x[0] > 5.001 || y[0] < 4.999 || y[0] > 5.001 {
+		t.Errorf("got (%v, %v), want (5, 5)", x[0], y[0])
+	}
+
+	xml, err := SerializeTransformer(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := DeserializeTransformer(xml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr2.Destroy()
+	x, y = []float64{10}, []float64{10}
+	if _, err := tr2.Transform(false, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if x[0] < 109.999 || x[0] > 110.001 || y[0] < 189.999 || y[0] > 190.001 {
+		t.Errorf("got (%v, %v), want (110, 190)", x[0], y[0])
+	}
+
+	if _, err := tr.Transform(false, []float64{1, 2}, []float64{1}, nil); err == nil {
+		t.Error("no error with mismatched coordinates")
+	}
+	tr.Destroy()
+	if _, err := tr.Transform(false, x, y, nil); err != ErrTransformerDestroyed {
+		t.Errorf("got %v, want ErrTransformerDestroyed", err)
+	}
+	if _, err := tr.Transform(false, x, y, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	if _, _, _, err := SuggestedWarpOutput(nil, tr2); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestTPSTransformer(t *testing.T) {
+	gcps := []GCP{
+		{ID: "1", Pixel: 0, Line: 0, X: 100, Y: 200},
+		{ID: "2", Pixel: 10, Line: 0, X: 110, Y: 200},
+		{ID: "3", Pixel: 0, Line: 10, X: 100, Y: 190},
+		{ID: "4", Pixel: 10, Line: 10, X: 112, Y: 188},
+		{ID: "5", Pixel: 5, Line: 5, X: 105.5, Y: 194.5},
+	}
+	tr, err := CreateTPSTransformer(gcps, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Destroy()
+
+	// the spline goes through the GCPs
+	x, y := []float64{10}, []float64{10}
+	if _, err := tr.Transform(false, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x[0]-112) > 1e-6 || math.Abs(y[0]-188) > 1e-6 {
+		t.Errorf("got (%v, %v), want (112, 188)", x[0], y[0])
+	}
+
+	x, y = []float64{3, 7}, []float64{7, 2}
+	ok, err := tr.Transform(false, x, y, nil)
+	if err != nil || !ok[0] || !ok[1] {
+		t.Fatal(err)
+	}
+	if _, err := tr.Transform(true, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range [][2]float64{{3, 7}, {7, 2}} {
+		if math.Abs(x[i]-want[0]) > 0.01 || math.Abs(y[i]-want[1]) > 0.01 {
+			t.Errorf("point %d: got (%v, %v), want %v", i, x[i], y[i], want)
+		}
+	}
+
+	if _, err := CreateTPSTransformer(gcps[:1], false); err == nil {
+		t.Error("no error with a single GCP")
+	}
+}
+
+func TestRPCTransformer(t *testing.T) {
+	// sample = 50 + 50 * (lon - 10), line = 50 - 50 * (lat - 45)
+	coeffs := func(first ...string) string {
+		terms := make([]string, 20)
+		for i := range terms {
+			terms[i] = "0"
+		}
+		copy(terms, first)
+		return strings.Join(terms, " ")
+	}
+	md := []string{
+		"LINE_OFF=50", "SAMP_OFF=50", "LAT_OFF=45", "LONG_OFF=10", "HEIGHT_OFF=0",
+		"LINE_SCALE=50", "SAMP_SCALE=50", "LAT_SCALE=1", "LONG_SCALE=1", "HEIGHT_SCALE=1",
+		"LINE_NUM_COEFF=" + coeffs("0", "0", "-1"),
+		"LINE_DEN_COEFF=" + coeffs("1"),
+		"SAMP_NUM_COEFF=" + coeffs("0", "1"),
+		"SAMP_DEN_COEFF=" + coeffs("1"),
+	}
+	tr, err := CreateRPCTransformer(md, false, 0.001, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Destroy()
+
+	x, y := []float64{75}, []float64{25}
+	if _, err := tr.Transform(false, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x[0]-10.5) > 0.02 || math.Abs(y[0]-45.5) > 0.02 {
+		t.Errorf("got (%v, %v), want about (10.5, 45.5)", x[0], y[0])
+	}
+	if _, err := tr.Transform(true, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x[0]-75) > 0.01 || math.Abs(y[0]-25) > 0.01 {
+		t.Errorf("got (%v, %v), want (75, 25)", x[0], y[0])
+	}
+
+	if _, err := CreateRPCTransformer(md[:4], false, 0.001, nil); err == nil {
+		t.Error("no error with incomplete RPC metadata")
+	}
+}
+
+func TestGeoLocTransformer(t *testing.T) {
+	if _, err := CreateGeoLocTransformer(nil, nil, false); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestGenImgProjTransformer(t *testing.T) {
+	src, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	tr, err := CreateGenImgProjTransformer2(src, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx, err := CreateApproxTransformer(tr, 0.125)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer approx.Destroy()
+	tr.Destroy() // owned by approx, must be a no-op
+
+	gt := src.GeoTransform()
+	x, y := []float64{0, 10}, []float64{0, 20}
+	if _, err := approx.Transform(false, x, y, nil); err != nil {
+		t.Fatal(err)
+	}
+	near := func(got, want float64) bool {
+		return got-want < 1e-6 && want-got < 1e-6
+	}
+	if !near(x[0], gt[0]) || !near(y[0], gt[3]) || !near(x[1], gt[0]+10*gt[1]) || !near(y[1], gt[3]+20*gt[5]) {
+		t.Errorf("got %v, %v", x, y)
+	}
+
+	outGT, pixels, lines, err := SuggestedWarpOutput(src, approx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pixels < src.RasterXSize()-1 || pixels > src.RasterXSize()+1 ||
+		lines < src.RasterYSize()-1 || lines > src.RasterYSize()+1 ||
+		!near(outGT[0], gt[0]) || !near(outGT[3], gt[3]) {
+		t.Errorf("got %v %dx%d, want %v %dx%d", outGT, pixels, lines, gt, src.RasterXSize(), src.RasterYSize())
+	}
+}
+
+func TestClose(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 10, 10, 1, Byte, nil)
+	band, _ := ds.RasterBand(1)
+	parent := band.GetDataset()
+	// the dataset of the band is borrowed from ds, closing it does nothing
+	if err := parent.Close(); err != nil {
+		t.Errorf("close through band: %v", err)
+	}
+	if n := ds.RasterCount(); n != 1 {
+		t.Errorf("dataset closed through its band has %d bands", n)
+	}
+	if err := ds.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Close(); err != nil {
+		t.Errorf("second close: %v", err)
+	}
+	if !band.GetDataset().closed() {
+		t.Error("dataset of a band of a closed dataset not closed")
+	}
+
+	geom, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := geom
+	if err := geom.Close(); err != nil {
+		t.Fatal(err)
+	}
+	copied.Close()
+	copied.Destroy()
+
+	line, _ := CreateFromWKT("LINESTRING (0 0,1 1)", SpatialReference{})
+	multi := line.ForceToMultiLineString()
+	line.Close() // consumed by ForceToMultiLineString
+	multi.Close()
+
+	fd := CreateFeatureDefinition("test")
+	feature := fd.Create()
+	point, _ := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	feature.SetGeometryDirectly(point)
+	point.Close() // owned by the feature
+	borrowed := feature.Geometry()
+	borrowed.Close() // only detached
+	if feature.Geometry().cval == nil {
+		t.Error("borrowed geometry destroyed")
+	}
+	feature.Close()
+	feature.Close()
+	if err := fd.Close(); err != nil {
+		t.Error(err)
+	}
+	fd.Close()
+
+	tr, err := CreateGCPTransformer([]GCP{
+		{Pixel: 0, Line: 0, X: 0, Y: 0},
+		{Pixel: 1, Line: 0, X: 1, Y: 0},
+		{Pixel: 0, Line: 1, X: 0, Y: 1},
+	}, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Close(); err != nil {
+		t.Error(err)
+	}
+	tr.Close()
+}
+
+func TestFinalizers(t *testing.T) {
+	EnableFinalizers(true)
+	defer EnableFinalizers(false)
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		geom, _ := CreateFromWKT("POINT (1 2)", SpatialReference{})
+		_ = geom.Buffer(1, 8)
+		ds := drv.Create("", 10, 10, 1, Byte, nil)
+		if i%2 == 0 {
+			ds.Close()
+		}
+	}
+	ds := drv.Create("", 10, 10, 1, Byte, nil)
+	band, _ := ds.RasterBand(1)
+	ds = nil
+	runtime.GC()
+	runtime.GC()
+	// the band keeps its dataset alive
+	if err := band.Fill(1, 0); err != nil {
+		t.Error(err)
+	}
+	if band.Checksum(0, 0, 10, 10) == 0 {
+		t.Error("band not filled")
+	}
+}
+
+func TestInteger64(t *testing.T) {
+	vds, ok := OGRDriverByName("Memory").Create("int64", nil)
+	if !ok {
+		t.Fatal("failed to create memory datasource")
+	}
+	defer vds.Destroy()
+	layer, err := vds.CreateLayer("osm", SpatialReference{}, GT_None, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, ftype := range map[string]FieldType{"id": FT_Integer64, "refs": FT_Integer64List} {
+		fd := CreateFieldDefinition(name, ftype)
+		layer.CreateField(fd, false)
+		fd.Destroy()
+	}
+
+	const big = int64(1) << 40
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	if err := feature.SetFID(big); err != nil {
+		t.Fatal(err)
+	}
+	id, refs := feature.FieldIndex("id"), feature.FieldIndex("refs")
+	feature.SetFieldInteger64(id, big+1)
+	feature.SetFieldInteger64List(refs, []int64{big + 2, -big})
+	if err := layer.CreateFeature(&feature); err != nil {
+		t.Fatal(err)
+	}
+
+	got := layer.Feature(big)
+	defer got.Destroy()
+	if got.FID() != big {
+		t.Fatalf("got FID %d, want %d", got.FID(), big)
+	}
+	if v := got.FieldAsInteger64(id); v != big+1 {
+		t.Errorf("got %d, want %d", v, big+1)
+	}
+	if l := got.FieldAsInteger64List(refs); len(l) != 2 || l[0] != big+2 || l[1] != -big {
+		t.Errorf("got %v", l)
+	}
+	if v := got.Values()["id"]; v != big+1 {
+		t.Errorf("got value %v (%T)", v, v)
+	}
+	var r struct {
+		ID   int64    `ogr:"id"`
+		Refs []uint64 `ogr:"refs"`
+	}
+	if err := got.Scan(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != big+1 || r.Refs[0] != uint64(big+2) {
+		t.Errorf("invalid scan: %+v", r)
+	}
+	if n, _ := layer.FeatureCount(true); n != 1 {
+		t.Errorf("got %d features, want 1", n)
+	}
+	if err := layer.DeleteFeature(big); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFieldSchema(t *testing.T) {
+	if VERSION_NUM < 3070000 {
+		t.Skip("field comments require GDAL 3.7")
+	}
+	drv, err := GetDriverByName("GPKG")
+	if err != nil {
+		t.Skip(err)
+	}
+	const name = "/vsimem/schema.gpkg"
+	ds := drv.Create(name, 0, 0, 0, Unknown, nil)
+	if ds == nil {
+		t.Fatal("failed to create dataset")
+	}
+	defer drv.DeleteDataset(name)
+	defer ds.Close()
+
+	coded, err := CreateCodedFieldDomain("kinds", "feature kinds", FT_String, FST_None,
+		[]CodedValue{{"r", "road"}, {"b", "building"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coded.Destroy()
+	if err := ds.AddFieldDomain(coded); err != nil {
+		t.Fatal(err)
+	}
+	ranged, err := CreateRangeFieldDomain("levels", "", FT_Integer, FST_None, 0, true, math.Inf(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ranged.Destroy()
+	if err := ds.AddFieldDomain(ranged); err != nil {
+		t.Fatal(err)
+	}
+
+	layer, err := ds.CreateLayer("schema", SpatialReference{}, GT_Point, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flag := CreateFieldDefinition("flag", FT_Integer)
+	flag.SetSubType(FST_Boolean)
+	flag.SetNullable(false)
+	flag.SetDefault("1")
+	kind := CreateFieldDefinition("kind", FT_String)
+	kind.SetUnique(true)
+	kind.SetAlternativeName("Kind")
+	kind.SetComment("kind of feature")
+	kind.SetDomainName("kinds")
+	for _, fd := range []FieldDefinition{flag, kind} {
+		if err := layer.CreateField(fd, false); err != nil {
+			t.Fatal(err)
+		}
+		fd.Destroy()
+	}
+
+	defn := layer.Definition()
+	flag = defn.FieldDefinition(defn.FieldIndex("flag"))
+	if flag.SubType() != FST_Boolean || flag.IsNullable() || flag.Default() != "1" {
+		t.Errorf("flag: subtype %s, nullable %v, default %q", flag.SubType().Name(), flag.IsNullable(), flag.Default())
+	}
+	kind = defn.FieldDefinition(defn.FieldIndex("kind"))
+	if !kind.IsUnique() || kind.AlternativeName() != "Kind" || kind.Comment() != "kind of feature" || kind.DomainName() != "kinds" {
+		t.Errorf("kind: unique %v, alternative name %q, comment %q, domain %q",
+			kind.IsUnique(), kind.AlternativeName(), kind.Comment(), kind.DomainName())
+	}
+
+	names := ds.FieldDomainNames()
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"kinds", "levels"}) {
+		t.Errorf("got domains %v", names)
+	}
+	domain, err := ds.FieldDomain("kinds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain.Type() != FDT_Coded || len(domain.CodedValues()) != 2 || domain.CodedValues()[1].Value != "building" {
+		t.Errorf("invalid coded domain: %v", domain.CodedValues())
+	}
+	domain, err = ds.FieldDomain("levels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	min, minIncl, max, _ := domain.Range()
+	if domain.Type() != FDT_Range || min != 0 || !minIncl || !math.IsInf(max, 1) {
+		t.Errorf("invalid range domain: %v %v %v", min, minIncl, max)
+	}
+	if err := ds.DeleteFieldDomain("levels"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGeometryFields(t *testing.T) {
+	vds, ok := OGRDriverByName("Memory").Create("geomfields", nil)
+	if !ok {
+		t.Fatal("failed to create memory datasource")
+	}
+	defer vds.Destroy()
+	layer, err := vds.CreateLayer("buildings", SpatialReference{}, GT_None, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, geomType := range map[string]GeometryType{"footprint": GT_Polygon, "centroid": GT_Point} {
+		gfd := CreateGeometryFieldDefinition(name, geomType)
+		gfd.SetNullable(false)
+		if err := layer.CreateGeomField(gfd, false); err != nil {
+			t.Fatal(err)
+		}
+		gfd.Destroy()
+	}
+	defn := layer.Definition()
+	if n := defn.GeometryFieldCount(); n != 2 {
+		t.Fatalf("got %d geometry fields, want 2", n)
+	}
+	footprint, centroid := defn.GeometryFieldIndex("footprint"), defn.GeometryFieldIndex("centroid")
+	if gfd := defn.GeometryFieldDefinition(centroid); gfd.Type() != GT_Point || gfd.IsNullable() {
+		t.Errorf("invalid centroid field: %d %v", gfd.Type(), gfd.IsNullable())
+	}
+
+	feature := defn.Create()
+	defer feature.Destroy()
+	poly, _ := CreateFromWKT("POLYGON ((0 0,4 0,4 2,0 2,0 0))", SpatialReference{})
+	defer poly.Destroy()
+	point, _ := CreateFromWKT("POINT (2 1)", SpatialReference{})
+	if err := feature.SetGeometryField(footprint, poly); err != nil {
+		t.Fatal(err)
+	}
+	if err := feature.SetGeometryFieldDirectly(centroid, point); err != nil {
+		t.Fatal(err)
+	}
+	if err := layer.CreateFeature(&feature); err != nil {
+		t.Fatal(err)
+	}
+	if wkt, _ := feature.GeometryField(centroid).ToWKT(); wkt != "POINT (2 1)" {
+		t.Errorf("got centroid %s", wkt)
+	}
+
+	env, err := layer.ExtentEx(footprint, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.MaxX() != 4 || env.MaxY() != 2 {
+		t.Errorf("invalid footprint extent: %v", env)
+	}
+	layer.SetSpatialFilterRectEx(centroid, 3, 0, 5, 2)
+	if n, _ := layer.FeatureCount(true); n != 0 {
+		t.Errorf("centroid filter matched %d features", n)
+	}
+	layer.SetSpatialFilterRectEx(footprint, 3, 0, 5, 2)
+	if n, _ := layer.FeatureCount(true); n != 1 {
+		t.Errorf("footprint filter matched %d features", n)
+	}
+}
+
+func TestCurveGeometry(t *testing.T) {
+	arc, err := CreateFromWKT("CIRCULARSTRING Z (0 0 1,1 1 1,2 0 1)", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer arc.Destroy()
+	if arc.Type() != GT_CircularStringZ || !arc.Type().IsNonLinear() || !arc.HasCurveGeometry(true) {
+		t.Fatalf("invalid curve type %s", arc.Type().Name())
+	}
+	if GT_CircularStringZ.Linear() != GT_LineString25D || GT_LineString.Curve() != GT_CompoundCurve {
+		t.Error("invalid linear or curve type")
+	}
+
+	linear := arc.GetLinearGeometry(0, nil)
+	defer linear.Destroy()
+	if linear.Type() != GT_LineString25D || linear.PointCount() <= 3 {
+		t.Errorf("invalid linear geometry %s with %d points", linear.Type().Name(), linear.PointCount())
+	}
+	curve := linear.GetCurveGeometry(nil)
+	defer curve.Destroy()
+	if curve.Type().Flatten() != GT_CircularString {
+		t.Errorf("arc not recognized, got %s", curve.Type().Name())
+	}
+
+	wkb, err := arc.ToISOWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := CreateFromWKB(wkb, SpatialReference{}, len(wkb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parsed.Destroy()
+	if wkt, _ := parsed.ToISOWKT(); wkt != "CIRCULARSTRING Z (0 0 1,1 1 1,2 0 1)" {
+		t.Errorf("got %s", wkt)
+	}
+}
+
+func TestMeasuredGeometry(t *testing.T) {
+	line := Create(GT_LineStringZM)
+	defer line.Destroy()
+	line.AddPointZM(0, 0, 1, 10)
+	line.AddPointZM(1, 1, 2, 20)
+	if !line.Is3D() || !line.IsMeasured() {
+		t.Fatal("expected 3D measured line")
+	}
+	if x, y, z, m := line.PointZM(1); x != 1 || y != 1 || z != 2 || m != 20 {
+		t.Errorf("got %v %v %v %v", x, y, z, m)
+	}
+	line.SetPointM(0, 5, 5, 15)
+	if line.M(0) != 15 {
+		t.Errorf("got M %v", line.M(0))
+	}
+
+	wkt, err := line.ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := CreateFromWKT(wkt, SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parsed.Destroy()
+	if parsed.Type() != GT_LineStringZM || parsed.M(1) != 20 {
+		t.Errorf("M lost in WKT %s", wkt)
+	}
+
+	wkb, err := line.ToWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromWKB, err := CreateFromWKB(wkb, SpatialReference{}, len(wkb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromWKB.Destroy()
+	if !fromWKB.Equals(line) || fromWKB.M(0) != 15 {
+		t.Error("M lost in WKB")
+	}
+
+	line.Set3D(false)
+	if line.Type() != GT_LineStringM || !line.Type().HasM() || line.Type().HasZ() {
+		t.Errorf("got %s", line.Type().Name())
+	}
+	line.SetMeasured(false)
+	if line.Type() != GT_LineString || line.CoordinateDimension() != 2 {
+		t.Errorf("got %s", line.Type().Name())
+	}
+	if GT_Point.SetModifier(true, true) != GT_PointZM {
+		t.Error("invalid modifier")
+	}
+}
+
+func TestBulkPoints(t *testing.T) {
+	line := Create(GT_LineString)
+	defer line.Destroy()
+	if err := line.SetPoints([]float64{0, 1, 2}, []float64{3, 4, 5}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if line.PointCount() != 3 || line.CoordinateDimension() != 2 {
+		t.Fatalf("got %d points in %d dimensions", line.PointCount(), line.CoordinateDimension())
+	}
+	xs, ys, zs := line.Points()
+	if !slices.Equal(xs, []float64{0, 1, 2}) || !slices.Equal(ys, []float64{3, 4, 5}) || !slices.Equal(zs, []float64{0, 0, 0}) {
+		t.Errorf("got %v %v %v", xs, ys, zs)
+	}
+	if err := line.SetPoints(xs, ys[:2], nil); err == nil {
+		t.Error("expected error on mismatched lengths")
+	}
+
+	if err := line.SetPointsStrided([]float64{0, 1, 2, 3, 4, 5}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if x, y, z := line.Point(1); line.PointCount() != 2 || x != 3 || y != 4 || z != 5 {
+		t.Errorf("got %d points, last %v %v %v", line.PointCount(), x, y, z)
+	}
+	buf := make([]float64, 8)
+	n, err := line.PointsStrided(buf, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !slices.Equal(buf, []float64{0, 1, 2, 0, 3, 4, 5, 0}) {
+		t.Errorf("got %d points %v", n, buf)
+	}
+	if _, err := line.PointsStrided(buf[:4], 3); err == nil {
+		t.Error("expected error on short buffer")
+	}
+}
+
+func TestVSIMem(t *testing.T) {
+	data, err := os.ReadFile("test/small_world.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := OpenBytes(data, ReadOnly, []string{"GTiff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := ds.FileList()[0]
+	if ds.RasterXSize() != 400 || ds.RasterYSize() != 200 {
+		t.Errorf("got size %dx%d", ds.RasterXSize(), ds.RasterYSize())
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeTo(&buf, ds, "PNG", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+		t.Error("invalid PNG signature")
+	}
+	if err := EncodeTo(io.Discard, ds, "ENVI", nil); err == nil {
+		t.Error("ENVI dataset encoded without its .hdr sidecar")
+	}
+	ds.Close()
+	if _, err := (VSIMemFile{name}).Bytes(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("%s not removed on close", name)
+	}
+
+	png, err := OpenBytes(buf.Bytes(), ReadOnly, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer png.Close()
+	if png.Driver().ShortName() != "PNG" || png.RasterXSize() != 400 {
+		t.Errorf("got %s raster of width %d", png.Driver().ShortName(), png.RasterXSize())
+	}
+	if _, err := OpenBytes([]byte("not a dataset"), ReadOnly, nil); err == nil {
+		t.Error("expected error opening invalid data")
+	}
+
+	f, err := CreateVSIMemFile("/vsimem/test.txt", []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := f.Bytes(); err != nil || string(b) != "hello" {
+		t.Errorf("got %q, %v", b, err)
+	}
+	if err := f.Unlink(); err != nil {
+		t.Error(err)
+	}
+	if err := f.Unlink(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+	if _, err := CreateVSIMemFile("test.txt", nil); err == nil {
+		t.Error("expected error on name outside /vsimem/")
+	}
+}
+
+func TestVSIHandler(t *testing.T) {
+	data, err := os.ReadFile("test/small_world.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"bucket/scene.tif": {Data: data}}
+	if err := RegisterVSIHandler("/vsigotest/", fsys); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := Open("/vsigotest/bucket/scene.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	direct, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer direct.Close()
+
+	nx, ny := ds.RasterXSize(), ds.RasterYSize()
+	if nx != 400 || ny != 200 {
+		t.Fatalf("got size %dx%d", nx, ny)
+	}
+	got := make([]byte, nx*ny)
+	want := make([]byte, nx*ny)
+	for i := 1; i <= ds.RasterCount(); i++ {
+		band, _ := ds.RasterBand(i)
+		if err := band.IO(Read, 0, 0, nx, ny, got, nx, ny, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		band, _ = direct.RasterBand(i)
+		if err := band.IO(Read, 0, 0, nx, ny, want, nx, ny, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("band %d differs from the file on disk", i)
+		}
+	}
+
+	if _, err := Open("/vsigotest/bucket/missing.tif", ReadOnly); err == nil {
+		t.Error("expected error opening missing file")
+	}
+	if err := RegisterVSIHandler("vsigotest", fsys); err == nil {
+		t.Error("expected error on invalid prefix")
+	}
+}
+
+func TestImage(t *testing.T) {
+	ds, err := Open("test/small_world.tif", ReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	img, err := ds.Image(Window{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rgba, ok := img.(*image.RGBA)
+	if !ok || rgba.Bounds() != image.Rect(0, 0, 400, 200) {
+		t.Fatalf("got %T of bounds %v", img, img.Bounds())
+	}
+	band, _ := ds.RasterBand(2)
+	green, err := ReadWindow[uint8](band, Window{XOff: 10, YOff: 20, XSize: 1, YSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := rgba.RGBAAt(10, 20); c.G != green[0] || c.A != 0xff {
+		t.Errorf("got %v, want green %d", c, green[0])
+	}
+	thumb, err := ds.Image(Window{XSize: 400, YSize: 200, BufXSize: 100, BufYSize: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Bounds() != image.Rect(0, 0, 100, 50) {
+		t.Errorf("got thumbnail bounds %v", thumb.Bounds())
+	}
+
+	palette := color.Palette{color.NRGBA{0, 0, 0, 0xff}, color.NRGBA{0xff, 0, 0, 0xff}}
+	paletted := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+	paletted.SetColorIndex(1, 2, 1)
+	mem, err := FromImage(paletted, "MEM", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	img, err = mem.Image(Window{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := img.(*image.Paletted); !ok || p.ColorIndexAt(1, 2) != 1 || p.At(1, 2) != palette[1] {
+		t.Errorf("got %T, color %v", img, img.At(1, 2))
+	}
+
+	gray := image.NewGray16(image.Rect(0, 0, 3, 2))
+	gray.SetGray16(2, 1, color.Gray16{Y: 4000})
+	tif, err := FromImage(gray, "GTiff", "/vsimem/image.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIMemFile{"/vsimem/image.tif"}.Unlink()
+	defer tif.Close()
+	img, err = tif.Image(Window{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, ok := img.(*image.Gray16); !ok || g.Gray16At(2, 1).Y != 4000 {
+		t.Errorf("got %T, color %v", img, img.At(2, 1))
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	nrgba.SetNRGBA(0, 0, color.NRGBA{0xff, 0x80, 0, 0x80})
+	png, err := FromImage(nrgba, "PNG", "/vsimem/image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer VSIMemFile{"/vsimem/image.png"}.Unlink()
+	defer png.Close()
+	if png.RasterCount() != 4 {
+		t.Fatalf("got %d bands", png.RasterCount())
+	}
+	img, err = png.Image(Window{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c.R != 0xff || c.A != 0x80 {
+		t.Errorf("got %v", c)
+	}
+}
+
+func TestMemDataset(t *testing.T) {
+	src := make([]uint8, 16)
+	src[0] = 1
+	gt := [6]float64{100, 10, 0, 200, 0, -10}
+	srcDS, err := NewMemDataset(4, 4, [][]uint8{src}, gt, SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srcDS.Close()
+	if srcDS.GeoTransform() != gt || srcDS.RasterCount() != 1 {
+		t.Errorf("got geotransform %v and %d bands", srcDS.GeoTransform(), srcDS.RasterCount())
+	}
+
+	dst := make([]float32, 16)
+	dstDS, err := NewMemDataset(4, 4, [][]float32{dst}, gt, SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dstDS.Close()
+	srcBand, _ := srcDS.RasterBand(1)
+	dstBand, _ := dstDS.RasterBand(1)
+	if err := srcBand.ComputeProximity(*dstBand, []string{"VALUES=1", "DISTUNITS=PIXEL"}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if dst[0] != 0 || dst[3] != 3 || math.Abs(float64(dst[5])-math.Sqrt2) > 1e-6 {
+		t.Errorf("proximity not written to the Go buffer: %v", dst)
+	}
+
+	if err := WriteWindow(srcBand, Window{XOff: 3, YOff: 3, XSize: 1, YSize: 1}, []uint8{7}); err != nil {
+		t.Fatal(err)
+	}
+	if src[15] != 7 {
+		t.Errorf("write not seen in the Go buffer: %v", src)
+	}
+	src[1] = 9
+	if v, _ := ReadWindow[uint8](srcBand, Window{XOff: 1, XSize: 1, YSize: 1}); v[0] != 9 {
+		t.Errorf("Go write not seen by GDAL: %v", v)
+	}
+
+	if _, err := NewMemDataset(4, 4, [][]uint8{src[:8]}, gt, SpatialReference{}); err == nil {
+		t.Error("expected error on short band")
+	}
+}
+
+func TestIterators(t *testing.T) {
+	geom, err := CreateFromWKT("MULTILINESTRING ((0 0,1 1),(2 2,3 3,4 4))", SpatialReference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	var points int
+	for part := range geom.Parts() {
+		for i, p := range part.PointSeq() {
+			if p[0] != p[1] || (i == 0 && p[0] != 0 && p[0] != 2) {
+				t.Errorf("invalid point %d: %v", i, p)
+			}
+			points++
+		}
+	}
+	if points != 5 {
+		t.Errorf("got %d points, want 5", points)
+	}
+
+	found := false
+	for driver := range Drivers() {
+		if driver.ShortName() == "MEM" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("MEM driver not enumerated")
+	}
+	if n := len(slices.Collect(OGRDrivers())); n != OGRDriverCount() {
+		t.Errorf("got %d OGR drivers, want %d", n, OGRDriverCount())
+	}
+
+	ds, err := OpenEx("test/poly.shp", VectorDrivers|ReadOnly, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	for layer := range ds.Layers() {
+		if layer.Name() != "poly" {
+			t.Errorf("invalid layer %s", layer.Name())
+		}
+	}
+}
+
+func TestUseAfterClose(t *testing.T) {
+	var nilDataset *Dataset
+	if n := nilDataset.RasterCount(); n != 0 {
+		t.Errorf("nil dataset has %d bands", n)
+	}
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 10, 10, 1, Byte, nil)
+	band, _ := ds.RasterBand(1)
+	ds.Close()
+	if _, err := ds.RasterBand(1); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	if n := band.XSize(); n != 0 {
+		t.Errorf("band of a closed dataset has width %d", n)
+	}
+	if err := band.Fill(1, 0); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	if _, err := ReadWindow[uint8](band, Window{XSize: 1, YSize: 1}); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	noop := func(blk Block, in, out [][]uint8) error { return nil }
+	if err := ProcessBlocks(context.Background(), []*RasterBand{band, nil}, nil, 1, noop); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+
+	vds, ok := OGRDriverByName("Memory").Create("closed", nil)
+	if !ok {
+		t.Fatal("failed to create memory datasource")
+	}
+	layer, err := vds.CreateLayer("points", SpatialReference{}, GT_Point, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vds.Destroy()
+	if f := layer.NextFeature(); f != nil {
+		t.Error("got a feature from a closed data source")
+	}
+	if _, err := vds.CreateLayer("points", SpatialReference{}, GT_Point, nil); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+
+	owned := CreateFeatureDefinition("owned")
+	nameDefn := CreateFieldDefinition("name", FT_String)
+	owned.AddFieldDefinition(nameDefn)
+	nameDefn.Destroy()
+	field := owned.FieldDefinition(0)
+	geomField := owned.GeometryFieldDefinition(0)
+	owned.Release()
+	if name := field.Name(); name != "" {
+		t.Errorf("field of a released definition has name %q", name)
+	}
+	if !geomField.closed() {
+		t.Error("geometry field of a released definition not closed")
+	}
+
+	fd := CreateFeatureDefinition("test")
+	defer fd.Destroy()
+	feature := fd.Create()
+	point, _ := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	feature.SetGeometryDirectly(point)
+	other := fd.Create()
+	defer other.Destroy()
+	if err := other.SetGeometryDirectly(point); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	geom := feature.Geometry()
+	feature.Destroy()
+	if _, err := geom.ToWKT(); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	if i := feature.FieldIndex("name"); i != -1 {
+		t.Errorf("closed feature has field index %d", i)
+	}
+	if i := (FeatureDefinition{}).FieldIndex("name"); i != -1 {
+		t.Errorf("nil feature definition has field index %d", i)
+	}
+	if i := feature.GeometryFieldIndex("geom"); i != -1 {
+		t.Errorf("closed feature has geometry field index %d", i)
+	}
+	if i := (FeatureDefinition{}).GeometryFieldIndex("geom"); i != -1 {
+		t.Errorf("nil feature definition has geometry field index %d", i)
+	}
+
+	if _, err := CreateFromJson("not json"); err == nil {
+		t.Error("invalid GeoJSON accepted")
+	}
+	if _, err := OpenDataSource("testdata/missing.shp", 0); err == nil {
+		t.Error("missing data source opened")
+	}
+	if _, err := OpenShared("testdata/missing.tif", ReadOnly); err == nil {
+		t.Error("missing dataset opened")
+	}
+}
+
+func TestFeatureMarshal(t *testing.T) {
+	type record struct {
+		Name  string    `ogr:"name"`
+		Count *int      `ogr:"count"`
+		Value float32   `ogr:"value"`
+		Codes []uint8   `ogr:"codes"`
+		Date  time.Time `ogr:"date"`
+		Skip  string    `ogr:"-"`
+	}
+	vds, ok := OGRDriverByName("Memory").Create("marshal", nil)
+	if !ok {
+		t.Fatal("failed to create memory datasource")
+	}
+	defer vds.Destroy()
+	layer, err := vds.CreateLayer("records", SpatialReference{}, GT_None, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []struct {
+		name  string
+		ftype FieldType
+	}{
+		{"name", FT_String},
+		{"count", FT_Integer},
+		{"value", FT_Real},
+		{"codes", FT_IntegerList},
+		{"date", FT_DateTime},
+	} {
+		fd := CreateFieldDefinition(field.name, field.ftype)
+		layer.CreateField(fd, false)
+		fd.Destroy()
+	}
+
+	count := 3
+	date := time.Date(2020, 5, 17, 12, 30, 0, 0, time.UTC)
+	records := []record{
+		{Name: "a", Count: &count, Value: 1.5, Codes: []uint8{1, 2}, Date: date, Skip: "x"},
+		{Name: "b"},
+	}
+	if err := layer.WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*record
+	if err := layer.ScanAll(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2", len(got))
+	}
+	first := got[0]
+	if first.Name != "a" || first.Count == nil || *first.Count != 3 || first.Value != 1.5 ||
+		len(first.Codes) != 2 || first.Codes[1] != 2 || !first.Date.Equal(date) || first.Skip != "" {
+		t.Errorf("invalid first record: %+v", first)
+	}
+	if got[1].Name != "b" || got[1].Count != nil {
+		t.Errorf("invalid second record: %+v", got[1])
+	}
+
+	layer.ResetReading()
+	feature := layer.NextFeature()
+	defer feature.Destroy()
+	values := feature.Values()
+	if values["name"] != "a" || values["count"] != 3 || values["value"] != 1.5 {
+		t.Errorf("invalid values: %v", values)
+	}
+	var r record
+	if err := feature.Scan(r); err == nil {
+		t.Error("scanned into a non-pointer")
+	}
+	var narrow struct {
+		Codes []uint8 `ogr:"codes"`
+	}
+	feature.SetFieldIntegerList(3, []int{1, 256})
+	if err := feature.Scan(&narrow); err == nil {
+		t.Error("overflowing value scanned")
+	}
+	var unsigned struct {
+		Count uint `ogr:"count"`
+	}
+	feature.SetFieldInteger(1, -1)
+	if err := feature.Scan(&unsigned); err == nil {
+		t.Error("negative value scanned into an unsigned field")
+	}
+	var wide struct {
+		Count uint64 `ogr:"count"`
+	}
+	wide.Count = math.MaxInt32 + 1
+	if err := feature.Fill(&wide); err == nil {
+		t.Error("overflowing value filled")
+	}
+	var fraction struct {
+		Count float64 `ogr:"count"`
+	}
+	fraction.Count = 1.5
+	if err := feature.Fill(&fraction); err == nil {
+		t.Error("fraction filled into an integer field")
+	}
+	var wideCodes struct {
+		Codes []int64 `ogr:"codes"`
+	}
+	wideCodes.Codes = []int64{1, math.MinInt32 - 1}
+	if err := feature.Fill(&wideCodes); err == nil {
+		t.Error("overflowing list value filled")
+	}
+	wide.Count = math.MaxUint64
+	if err := layer.WriteAll([]struct {
+		Count uint64 `ogr:"count"`
+	}{wide}); err == nil {
+		t.Error("overflowing value written")
+	}
+	r.Name = "c"
+	if err := feature.Fill(&r); err != nil {
+		t.Fatal(err)
+	}
+	if !feature.IsFieldNull(1) || feature.FieldAsString(0) != "c" {
+		t.Error("fields not filled")
+	}
+}
+
 func TestInvalidBand(t *testing.T) {
 	drv, err := GetDriverByName("MEM")
 	if err != nil {
@@ -196,6 +1898,79 @@ func TestInvalidBand(t *testing.T) {
 	}
 }
 
+func TestErrorMessage(t *testing.T) {
+	drv, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	PushQuietHandler()
+	err = drv.DeleteDataset("/vsimem/does/not/exist.tif")
+	PopHandler()
+	if err == nil {
+		t.Fatal("deleted a non-existent dataset")
+	}
+	if !errors.Is(err, ErrFailure) {
+		t.Errorf("error does not match ErrFailure: %v", err)
+	}
+	var gdalErr *Error
+	if !errors.As(err, &gdalErr) {
+		t.Fatalf("error is not a *Error: %T", err)
+	}
+	if gdalErr.Class != CE_Failure {
+		t.Errorf("invalid error class: %s", gdalErr.Class)
+	}
+	if gdalErr.Msg == "" {
+		t.Error("error message not captured")
+	}
+}
+
+func TestPushErrorHandler(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var classes []ErrorClass
+	PushErrorHandler(func(class ErrorClass, num int, msg string) {
+		classes = append(classes, class)
+	})
+	ds := drv.Create("", 1, 1, 1, Byte, []string{"GO_GDAL_BOGUS=YES"})
+	PopHandler()
+	if ds == nil {
+		t.Fatal("failed to create dataset")
+	}
+	defer ds.Close()
+	if len(classes) == 0 || classes[0] != CE_Warning {
+		t.Errorf("handler did not receive warning: %v", classes)
+	}
+}
+
+func TestCollectWarnings(t *testing.T) {
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ds *Dataset
+	warnings, err := CollectWarnings(func() error {
+		ds = drv.Create("", 1, 1, 1, Byte, []string{"GO_GDAL_BOGUS=YES"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds == nil {
+		t.Fatal("failed to create dataset")
+	}
+	defer ds.Close()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	if warnings[0].Msg == "" || !errors.Is(warnings[0], ErrWarning) {
+		t.Errorf("invalid warning: %+v", warnings[0])
+	}
+}
+
 func TestGetLayer(t *testing.T) {
 	if !HTTPEnabled() {
 		t.Skip()
@@ -259,3 +2034,48 @@ func BenchmarkRasterIO(b *testing.B) {
 	}
 	ds.Close()
 }
+
+func benchmarkLine(b *testing.B) Geometry {
+	const n = 100000
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	zs := make([]float64, n)
+	for i := range xs {
+		xs[i], ys[i], zs[i] = float64(i), float64(i), float64(i)
+	}
+	line := Create(GT_LineString25D)
+	if err := line.SetPoints(xs, ys, zs); err != nil {
+		b.Fatal(err)
+	}
+	return line
+}
+
+func BenchmarkGeometryPoint(b *testing.B) {
+	line := benchmarkLine(b)
+	defer line.Destroy()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < line.PointCount(); j++ {
+			line.Point(j)
+		}
+	}
+}
+
+func BenchmarkGeometryPoints(b *testing.B) {
+	line := benchmarkLine(b)
+	defer line.Destroy()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		line.Points()
+	}
+}
+
+func BenchmarkGeometryPointsStrided(b *testing.B) {
+	line := benchmarkLine(b)
+	defer line.Destroy()
+	buf := make([]float64, 3*line.PointCount())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		line.PointsStrided(buf, 3)
+	}
+}
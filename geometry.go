@@ -10,8 +10,12 @@ package gdal
 #cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
 */
 import "C"
-
-import "unsafe"
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"unsafe"
+)
 
 // List of well known binary geometry types
 type GeometryType uint32
@@ -36,47 +40,212 @@ const (
 	GT_GeometryCollection25D = GeometryType(C.wkbGeometryCollection25D)
 )
 
+// Measured variants of the simple feature geometry types
+const (
+	GT_PointM               = GeometryType(C.wkbPointM)
+	GT_PointZM              = GeometryType(C.wkbPointZM)
+	GT_LineStringM          = GeometryType(C.wkbLineStringM)
+	GT_LineStringZM         = GeometryType(C.wkbLineStringZM)
+	GT_PolygonM             = GeometryType(C.wkbPolygonM)
+	GT_PolygonZM            = GeometryType(C.wkbPolygonZM)
+	GT_MultiPointM          = GeometryType(C.wkbMultiPointM)
+	GT_MultiPointZM         = GeometryType(C.wkbMultiPointZM)
+	GT_MultiLineStringM     = GeometryType(C.wkbMultiLineStringM)
+	GT_MultiLineStringZM    = GeometryType(C.wkbMultiLineStringZM)
+	GT_MultiPolygonM        = GeometryType(C.wkbMultiPolygonM)
+	GT_MultiPolygonZM       = GeometryType(C.wkbMultiPolygonZM)
+	GT_GeometryCollectionM  = GeometryType(C.wkbGeometryCollectionM)
+	GT_GeometryCollectionZM = GeometryType(C.wkbGeometryCollectionZM)
+)
+
+// ISO SQL/MM curve and surface geometry types, with their Z, M and ZM variants
+const (
+	GT_CircularString      = GeometryType(C.wkbCircularString)
+	GT_CircularStringZ     = GeometryType(C.wkbCircularStringZ)
+	GT_CircularStringM     = GeometryType(C.wkbCircularStringM)
+	GT_CircularStringZM    = GeometryType(C.wkbCircularStringZM)
+	GT_CompoundCurve       = GeometryType(C.wkbCompoundCurve)
+	GT_CompoundCurveZ      = GeometryType(C.wkbCompoundCurveZ)
+	GT_CompoundCurveM      = GeometryType(C.wkbCompoundCurveM)
+	GT_CompoundCurveZM     = GeometryType(C.wkbCompoundCurveZM)
+	GT_CurvePolygon        = GeometryType(C.wkbCurvePolygon)
+	GT_CurvePolygonZ       = GeometryType(C.wkbCurvePolygonZ)
+	GT_CurvePolygonM       = GeometryType(C.wkbCurvePolygonM)
+	GT_CurvePolygonZM      = GeometryType(C.wkbCurvePolygonZM)
+	GT_MultiCurve          = GeometryType(C.wkbMultiCurve)
+	GT_MultiCurveZ         = GeometryType(C.wkbMultiCurveZ)
+	GT_MultiCurveM         = GeometryType(C.wkbMultiCurveM)
+	GT_MultiCurveZM        = GeometryType(C.wkbMultiCurveZM)
+	GT_MultiSurface        = GeometryType(C.wkbMultiSurface)
+	GT_MultiSurfaceZ       = GeometryType(C.wkbMultiSurfaceZ)
+	GT_MultiSurfaceM       = GeometryType(C.wkbMultiSurfaceM)
+	GT_MultiSurfaceZM      = GeometryType(C.wkbMultiSurfaceZM)
+	GT_Curve               = GeometryType(C.wkbCurve)
+	GT_CurveZ              = GeometryType(C.wkbCurveZ)
+	GT_CurveM              = GeometryType(C.wkbCurveM)
+	GT_CurveZM             = GeometryType(C.wkbCurveZM)
+	GT_Surface             = GeometryType(C.wkbSurface)
+	GT_SurfaceZ            = GeometryType(C.wkbSurfaceZ)
+	GT_SurfaceM            = GeometryType(C.wkbSurfaceM)
+	GT_SurfaceZM           = GeometryType(C.wkbSurfaceZM)
+	GT_PolyhedralSurface   = GeometryType(C.wkbPolyhedralSurface)
+	GT_PolyhedralSurfaceZ  = GeometryType(C.wkbPolyhedralSurfaceZ)
+	GT_PolyhedralSurfaceM  = GeometryType(C.wkbPolyhedralSurfaceM)
+	GT_PolyhedralSurfaceZM = GeometryType(C.wkbPolyhedralSurfaceZM)
+	GT_TIN                 = GeometryType(C.wkbTIN)
+	GT_TINZ                = GeometryType(C.wkbTINZ)
+	GT_TINM                = GeometryType(C.wkbTINM)
+	GT_TINZM               = GeometryType(C.wkbTINZM)
+	GT_Triangle            = GeometryType(C.wkbTriangle)
+	GT_TriangleZ           = GeometryType(C.wkbTriangleZ)
+	GT_TriangleM           = GeometryType(C.wkbTriangleM)
+	GT_TriangleZM          = GeometryType(C.wkbTriangleZM)
+)
+
+// Fetch the name of the geometry type
+func (gt GeometryType) Name() string {
+	name := C.OGRGeometryTypeToName(C.OGRwkbGeometryType(gt))
+	return C.GoString(name)
+}
+
+// Return the 2D base type of the geometry type
+func (gt GeometryType) Flatten() GeometryType {
+	return GeometryType(C.OGR_GT_Flatten(C.OGRwkbGeometryType(gt)))
+}
+
+// Return whether the geometry type has Z coordinates
+func (gt GeometryType) HasZ() bool {
+	return C.OGR_GT_HasZ(C.OGRwkbGeometryType(gt)) != 0
+}
+
+// Return whether the geometry type has M coordinates
+func (gt GeometryType) HasM() bool {
+	return C.OGR_GT_HasM(C.OGRwkbGeometryType(gt)) != 0
+}
+
+// Return the geometry type with or without Z and M coordinates
+func (gt GeometryType) SetModifier(hasZ, hasM bool) GeometryType {
+	return GeometryType(C.OGR_GT_SetModifier(C.OGRwkbGeometryType(gt), BoolToCInt(hasZ), BoolToCInt(hasM)))
+}
+
+// Return whether the geometry type is a curve or surface type, or a
+// collection that can hold them
+func (gt GeometryType) IsNonLinear() bool {
+	return C.OGR_GT_IsNonLinear(C.OGRwkbGeometryType(gt)) != 0
+}
+
+// Return the linear type matching a curve type, such as GT_LineString for
+// GT_CircularString, keeping the Z and M flags
+func (gt GeometryType) Linear() GeometryType {
+	return GeometryType(C.OGR_GT_GetLinear(C.OGRwkbGeometryType(gt)))
+}
+
+// Return the curve type matching a linear type, such as GT_CompoundCurve for
+// GT_LineString, keeping the Z and M flags
+func (gt GeometryType) Curve() GeometryType {
+	return GeometryType(C.OGR_GT_GetCurve(C.OGRwkbGeometryType(gt)))
+}
+
+// Set whether curve geometries are returned by the drivers as is.  When
+// disabled, they are approximated by their linear counterparts.  Curves are
+// enabled by default.
+func SetNonLinearGeometriesEnabled(enabled bool) {
+	C.OGRSetNonLinearGeometriesEnabledFlag(BoolToCInt(enabled))
+}
+
+// Return whether curve geometries are returned by the drivers as is
+func NonLinearGeometriesEnabled() bool {
+	return C.OGRGetNonLinearGeometriesEnabledFlag() != 0
+}
+
 type Geometry struct {
-	cval C.OGRGeometryH
+	cval   C.OGRGeometryH
+	life   *lifetime
+	parent *lifetime
+}
+
+// newGeometry returns a geometry owned by the caller
+func newGeometry(geom C.OGRGeometryH) Geometry {
+	if geom == nil {
+		return Geometry{}
+	}
+	return Geometry{
+		cval: geom,
+		life: newLifetime(func() { C.OGR_G_DestroyGeometry(geom) }),
+	}
+}
+
+// owner returns the lifetime of the geometry, or of its parent if borrowed
+func (geom Geometry) owner() *lifetime {
+	if geom.life != nil {
+		return geom.life
+	}
+	return geom.parent
 }
 
 //Create a geometry object from its well known binary representation
 func CreateFromWKB(wkb []uint8, srs SpatialReference, bytes int) (Geometry, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(srs)
 	cString := (*C.uchar)(unsafe.Pointer(&wkb[0]))
-	var newGeom Geometry
-	return newGeom, C.OGR_G_CreateFromWkb(
-		cString, srs.cval, &newGeom.cval, C.int(bytes),
+	var newGeom C.OGRGeometryH
+	err := C.OGR_G_CreateFromWkb(
+		cString, srs.cval, &newGeom, C.int(bytes),
 	).Err()
+	return newGeometry(newGeom), err
 }
 
 //Create a geometry object from its well known text representation
 func CreateFromWKT(wkt string, srs SpatialReference) (Geometry, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(srs)
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
-	var newGeom Geometry
-	return newGeom, C.OGR_G_CreateFromWkt(
-		&cString, srs.cval, &newGeom.cval,
+	var newGeom C.OGRGeometryH
+	err := C.OGR_G_CreateFromWkt(
+		&cString, srs.cval, &newGeom,
 	).Err()
+	return newGeometry(newGeom), err
 }
 
 //Create a geometry object from its GeoJSON representation
-func CreateFromJson(_json string) Geometry {
+func CreateFromJson(_json string) (Geometry, error) {
+	defer errorScope()()
 	cString := C.CString(_json)
 	defer C.free(unsafe.Pointer(cString))
-	var newGeom Geometry
-	newGeom.cval = C.OGR_G_CreateGeometryFromJson(cString)
-	return newGeom
+	newGeom := C.OGR_G_CreateGeometryFromJson(cString)
+	if newGeom == nil {
+		return Geometry{}, lastError(CE_Failure)
+	}
+	return newGeometry(newGeom), nil
 }
 
 // Destroy geometry object
 func (geometry Geometry) Destroy() {
-	C.OGR_G_DestroyGeometry(geometry.cval)
+	defer runtime.KeepAlive(geometry)
+	if geometry.closed() {
+		return
+	}
+	if geometry.life.release() {
+		C.OGR_G_DestroyGeometry(geometry.cval)
+	}
+}
+
+// Close destroys the geometry if it is owned by the caller.  Closing a
+// geometry that is already closed does nothing.
+func (geometry *Geometry) Close() error {
+	defer runtime.KeepAlive(geometry)
+	if geometry.cval != nil && geometry.life.owned() && geometry.life.release() {
+		C.OGR_G_DestroyGeometry(geometry.cval)
+	}
+	geometry.cval = nil
+	return nil
 }
 
 // Create an empty geometry of the desired type
 func Create(geomType GeometryType) Geometry {
 	geom := C.OGR_G_CreateGeometry(C.OGRwkbGeometryType(geomType))
-	return Geometry{geom}
+	return newGeometry(geom)
 }
 
 // Stroke arc to linestring
@@ -99,58 +268,180 @@ func ApproximateArcAngles(
 		C.double(startAngle),
 		C.double(endAngle),
 		C.double(stepSizeDegrees))
-	return Geometry{geom}
+	return newGeometry(geom)
 }
 
-// Convert to polygon
+// Convert to polygon, consuming the geometry
 func (geom Geometry) ForceToPolygon() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	geom.life.release()
 	newGeom := C.OGR_G_ForceToPolygon(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
-// Convert to multipolygon
+// Convert to multipolygon, consuming the geometry
 func (geom Geometry) ForceToMultiPolygon() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	geom.life.release()
 	newGeom := C.OGR_G_ForceToMultiPolygon(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
-// Convert to multipoint
+// Convert to multipoint, consuming the geometry
 func (geom Geometry) ForceToMultiPoint() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	geom.life.release()
 	newGeom := C.OGR_G_ForceToMultiPoint(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
-// Convert to multilinestring
+// Convert to multilinestring, consuming the geometry
 func (geom Geometry) ForceToMultiLineString() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	geom.life.release()
 	newGeom := C.OGR_G_ForceToMultiLineString(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Get the dimension of this geometry
 func (geom Geometry) Dimension() int {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	dim := C.OGR_G_GetDimension(geom.cval)
 	return int(dim)
 }
 
 // Get the dimension of the coordinates in this geometry
 func (geom Geometry) CoordinateDimension() int {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	dim := C.OGR_G_GetCoordinateDimension(geom.cval)
 	return int(dim)
 }
 
 // Set the dimension of the coordinates in this geometry
 func (geom Geometry) SetCoordinateDimension(dim int) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_SetCoordinateDimension(geom.cval, C.int(dim))
 }
 
+// Return whether the geometry has Z coordinates
+func (geom Geometry) Is3D() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
+	is3D := C.OGR_G_Is3D(geom.cval)
+	return is3D != 0
+}
+
+// Return whether the geometry has M coordinates
+func (geom Geometry) IsMeasured() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
+	measured := C.OGR_G_IsMeasured(geom.cval)
+	return measured != 0
+}
+
+// Add or remove the Z coordinates of the geometry
+func (geom Geometry) Set3D(is3D bool) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_Set3D(geom.cval, BoolToCInt(is3D))
+}
+
+// Add or remove the M coordinates of the geometry
+func (geom Geometry) SetMeasured(measured bool) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_SetMeasured(geom.cval, BoolToCInt(measured))
+}
+
 // Create a copy of this geometry
 func (geom Geometry) Clone() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Clone(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
+}
+
+// Return whether the geometry holds curves.  If lookForNonLinear is set,
+// curves that are actually straight lines, such as a compound curve made of
+// line strings, are ignored.
+func (geom Geometry) HasCurveGeometry(lookForNonLinear bool) bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
+	has := C.OGR_G_HasCurveGeometry(geom.cval, BoolToCInt(lookForNonLinear))
+	return has != 0
+}
+
+// Return a linear approximation of the geometry, its arcs being split in
+// segments spanning at most maxAngle degrees (0 for the default of 4
+// degrees).  The options are those of OGRGeometryFactory::curveToLineString,
+// such as ADD_INTERMEDIATE_POINT=YES.
+func (geom Geometry) GetLinearGeometry(maxAngle float64, options []string) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	newGeom := C.OGR_G_GetLinearGeometry(
+		geom.cval,
+		C.double(maxAngle),
+		(**C.char)(unsafe.Pointer(&cOpts[0])),
+	)
+	return newGeometry(newGeom)
+}
+
+// Return a curve geometry equivalent to the geometry, arcs approximated by
+// GetLinearGeometry being recognized as such
+func (geom Geometry) GetCurveGeometry(options []string) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	newGeom := C.OGR_G_GetCurveGeometry(geom.cval, (**C.char)(unsafe.Pointer(&cOpts[0])))
+	return newGeometry(newGeom)
 }
 
 // Compute and return the bounding envelope for this geometry
 func (geom Geometry) Envelope() Envelope {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Envelope{}
+	}
 	var env Envelope
 	C.OGR_G_GetEnvelope(geom.cval, &env.cval)
 	return env
@@ -160,47 +451,114 @@ func (geom Geometry) Envelope() Envelope {
 
 // Assign a geometry from well known binary data
 func (geom Geometry) FromWKB(wkb []uint8, bytes int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ErrClosed
+	}
 	cString := (*C.uchar)(unsafe.Pointer(&wkb[0]))
 	return C.OGR_G_ImportFromWkb(geom.cval, cString, C.int(bytes)).Err()
 }
 
-// Convert a geometry to well known binary data
+// Convert a geometry to well known binary data.  Measured geometries are
+// exported as ISO WKB, which keeps their M coordinates.
 func (geom Geometry) ToWKB() ([]uint8, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return nil, ErrClosed
+	}
+	if geom.IsMeasured() {
+		return geom.ToISOWKB()
+	}
 	b := make([]uint8, geom.WKBSize())
 	cString := (*C.uchar)(unsafe.Pointer(&b[0]))
 	err := C.OGR_G_ExportToWkb(geom.cval, C.OGRwkbByteOrder(C.wkbNDR), cString).Err()
 	return b, err
 }
 
+// Convert a geometry to ISO well known binary data, which supports curve
+// types and M coordinates
+func (geom Geometry) ToISOWKB() ([]uint8, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return nil, ErrClosed
+	}
+	b := make([]uint8, geom.WKBSize())
+	cString := (*C.uchar)(unsafe.Pointer(&b[0]))
+	err := C.OGR_G_ExportToIsoWkb(geom.cval, C.OGRwkbByteOrder(C.wkbNDR), cString).Err()
+	return b, err
+}
+
 // Returns size of related binary representation
 func (geom Geometry) WKBSize() int {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	size := C.OGR_G_WkbSize(geom.cval)
 	return int(size)
 }
 
 // Assign geometry object from its well known text representation
 func (geom Geometry) FromWKT(wkt string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ErrClosed
+	}
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OGR_G_ImportFromWkt(geom.cval, &cString).Err()
 }
 
-// Fetch geometry as WKT
+// Fetch geometry as WKT.  Measured geometries are exported as ISO WKT, such
+// as "POINT M (1 2 3)", which keeps their M coordinates.
 func (geom Geometry) ToWKT() (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return "", ErrClosed
+	}
+	if geom.IsMeasured() {
+		return geom.ToISOWKT()
+	}
 	var p *C.char
 	err := C.OGR_G_ExportToWkt(geom.cval, &p).Err()
 	wkt := C.GoString(p)
 	return wkt, err
 }
 
+// Fetch geometry as ISO WKT, such as "CIRCULARSTRING Z (0 0 1,1 1 1,2 0 1)"
+func (geom Geometry) ToISOWKT() (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return "", ErrClosed
+	}
+	var p *C.char
+	err := C.OGR_G_ExportToIsoWkt(geom.cval, &p).Err()
+	defer C.VSIFree(unsafe.Pointer(p))
+	return C.GoString(p), err
+}
+
 // Fetch geometry type
 func (geom Geometry) Type() GeometryType {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	gt := C.OGR_G_GetGeometryType(geom.cval)
 	return GeometryType(gt)
 }
 
 // Fetch geometry name
 func (geom Geometry) Name() string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	name := C.OGR_G_GetGeometryName(geom.cval)
 	return C.GoString(name)
 }
@@ -209,11 +567,19 @@ func (geom Geometry) Name() string {
 
 // Convert geometry to strictly 2D
 func (geom Geometry) FlattenTo2D() {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_FlattenTo2D(geom.cval)
 }
 
 // Force rings to be closed
 func (geom Geometry) CloseRings() {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_CloseRings(geom.cval)
 }
 
@@ -222,17 +588,25 @@ func CreateFromGML(gml string) Geometry {
 	cString := C.CString(gml)
 	defer C.free(unsafe.Pointer(cString))
 	geom := C.OGR_G_CreateFromGML(cString)
-	return Geometry{geom}
+	return newGeometry(geom)
 }
 
 // Convert a geometry to GML format
 func (geom Geometry) ToGML() string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	val := C.OGR_G_ExportToGML(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to GML format with options
 func (geom Geometry) ToGML_Ex(options []string) string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -247,18 +621,31 @@ func (geom Geometry) ToGML_Ex(options []string) string {
 
 // Convert a geometry to KML format
 func (geom Geometry) ToKML() string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	val := C.OGR_G_ExportToKML(geom.cval, nil)
 	return C.GoString(val)
 }
 
-// Convert a geometry to JSON format
+// Convert a geometry to JSON format.  GeoJSON has no M coordinates, so those
+// of measured geometries are dropped; use ToWKB or ToWKT to keep them.
 func (geom Geometry) ToJSON() string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	val := C.OGR_G_ExportToJson(geom.cval)
 	return C.GoString(val)
 }
 
 // Convert a geometry to JSON format with options
 func (geom Geometry) ToJSON_ex(options []string) string {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ""
+	}
 	length := len(options)
 	opts := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -273,118 +660,213 @@ func (geom Geometry) ToJSON_ex(options []string) string {
 
 // Fetch the spatial reference associated with this geometry
 func (geom Geometry) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return SpatialReference{}
+	}
 	spatialRef := C.OGR_G_GetSpatialReference(geom.cval)
-	return SpatialReference{spatialRef}
+	return SpatialReference{cval: spatialRef, parent: geom.owner()}
 }
 
 // Assign a spatial reference to this geometry
 func (geom Geometry) SetSpatialReference(spatialRef SpatialReference) {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(spatialRef)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_AssignSpatialReference(geom.cval, spatialRef.cval)
 }
 
 // Apply coordinate transformation to geometry
 func (geom Geometry) Transform(ct CoordinateTransform) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(ct)
+	if geom.closed() {
+		return ErrClosed
+	}
 	return C.OGR_G_Transform(geom.cval, ct.cval).Err()
 }
 
 // Transform geometry to new spatial reference system
 func (geom Geometry) TransformTo(sr SpatialReference) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(sr)
+	if geom.closed() {
+		return ErrClosed
+	}
 	return C.OGR_G_TransformTo(geom.cval, sr.cval).Err()
 }
 
 // Simplify the geometry
 func (geom Geometry) Simplify(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Simplify(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Simplify the geometry while preserving topology
 func (geom Geometry) SimplifyPreservingTopology(tolerance float64) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_SimplifyPreserveTopology(geom.cval, C.double(tolerance))
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Modify the geometry such that it has no line segment longer than the given distance
 func (geom Geometry) Segmentize(distance float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_Segmentize(geom.cval, C.double(distance))
 }
 
 // Return true if these features intersect
 func (geom Geometry) Intersects(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Intersects(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if these features are equal
 func (geom Geometry) Equals(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Equals(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the features are disjoint
 func (geom Geometry) Disjoint(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Disjoint(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature touches the other
 func (geom Geometry) Touches(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Touches(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this feature crosses the other
 func (geom Geometry) Crosses(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Crosses(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry is within the other
 func (geom Geometry) Within(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Within(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry contains the other
 func (geom Geometry) Contains(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Contains(geom.cval, other.cval)
 	return val != 0
 }
 
 // Return true if this geometry overlaps the other
 func (geom Geometry) Overlaps(other Geometry) bool {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_Overlaps(geom.cval, other.cval)
 	return val != 0
 }
 
 // Compute boundary for the geometry
 func (geom Geometry) Boundary() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Boundary(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute convex hull for the geometry
 func (geom Geometry) ConvexHull() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_ConvexHull(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute buffer of the geometry
 func (geom Geometry) Buffer(distance float64, segments int) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Buffer(geom.cval, C.double(distance), C.int(segments))
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute intersection of this geometry with the other
 func (geom Geometry) Intersection(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Intersection(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute union of this geometry with the other
 func (geom Geometry) Union(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Union(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Unimplemented: UnionCascaded
@@ -398,36 +880,63 @@ func (geom Geometry) Union(other Geometry) Geometry {
 
 // Compute difference between this geometry and the other
 func (geom Geometry) Difference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Difference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute symmetric difference between this geometry and the other
 func (geom Geometry) SymmetricDifference(other Geometry) Geometry {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_SymDifference(geom.cval, other.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Compute distance between thie geometry and the other
 func (geom Geometry) Distance(other Geometry) float64 {
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return 0
+	}
 	dist := C.OGR_G_Distance(geom.cval, other.cval)
 	return float64(dist)
 }
 
 // Compute length of geometry
 func (geom Geometry) Length() float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	length := C.OGR_G_Length(geom.cval)
 	return float64(length)
 }
 
 // Compute area of geometry
 func (geom Geometry) Area() float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	area := C.OGR_G_Area(geom.cval)
 	return float64(area)
 }
 
 // Compute centroid of geometry
 func (geom Geometry) Centroid() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	var centroid Geometry
 	C.OGR_G_Centroid(geom.cval, centroid.cval)
 	return centroid
@@ -435,65 +944,119 @@ func (geom Geometry) Centroid() Geometry {
 
 // Clear the geometry to its uninitialized state
 func (geom Geometry) Empty() {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_Empty(geom.cval)
 }
 
 // Test if the geometry is empty
 func (geom Geometry) IsEmpty() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_IsEmpty(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is valid
 func (geom Geometry) IsValid() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_IsValid(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is simple
 func (geom Geometry) IsSimple() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_IsSimple(geom.cval)
 	return val != 0
 }
 
 // Test if the geometry is a ring
 func (geom Geometry) IsRing() bool {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return false
+	}
 	val := C.OGR_G_IsRing(geom.cval)
 	return val != 0
 }
 
 // Polygonize a set of sparse edges
 func (geom Geometry) Polygonize() Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_Polygonize(geom.cval)
-	return Geometry{newGeom}
+	return newGeometry(newGeom)
 }
 
 // Fetch number of points in the geometry
 func (geom Geometry) PointCount() int {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	count := C.OGR_G_GetPointCount(geom.cval)
 	return int(count)
 }
 
 // Fetch the X coordinate of a point in the geometry
 func (geom Geometry) X(index int) float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	x := C.OGR_G_GetX(geom.cval, C.int(index))
 	return float64(x)
 }
 
 // Fetch the Y coordinate of a point in the geometry
 func (geom Geometry) Y(index int) float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	y := C.OGR_G_GetY(geom.cval, C.int(index))
 	return float64(y)
 }
 
 // Fetch the Z coordinate of a point in the geometry
 func (geom Geometry) Z(index int) float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	z := C.OGR_G_GetZ(geom.cval, C.int(index))
 	return float64(z)
 }
 
+// Fetch the M coordinate of a point in the geometry
+func (geom Geometry) M(index int) float64 {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
+	m := C.OGR_G_GetM(geom.cval, C.int(index))
+	return float64(m)
+}
+
 // Fetch the coordinates of a point in the geometry
 func (geom Geometry) Point(index int) (x, y, z float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0, 0, 0
+	}
 	C.OGR_G_GetPoint(
 		geom.cval,
 		C.int(index),
@@ -503,8 +1066,155 @@ func (geom Geometry) Point(index int) (x, y, z float64) {
 	return
 }
 
+// Fetch the coordinates of a point in the geometry, including M
+func (geom Geometry) PointZM(index int) (x, y, z, m float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0, 0, 0, 0
+	}
+	C.OGR_G_GetPointZM(
+		geom.cval,
+		C.int(index),
+		(*C.double)(&x),
+		(*C.double)(&y),
+		(*C.double)(&z),
+		(*C.double)(&m))
+	return
+}
+
+// PointSeq returns an iterator over the indexes and the x, y and z coordinates
+// of the points of the geometry
+func (geom Geometry) PointSeq() iter.Seq2[int, [3]float64] {
+	return func(yield func(int, [3]float64) bool) {
+		for i := 0; i < geom.PointCount(); i++ {
+			x, y, z := geom.Point(i)
+			if !yield(i, [3]float64{x, y, z}) {
+				return
+			}
+		}
+	}
+}
+
+// Fetch the x, y and z coordinates of all the points of the geometry at once
+func (geom Geometry) Points() (xs, ys, zs []float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return nil, nil, nil
+	}
+	n := geom.PointCount()
+	if n == 0 {
+		return nil, nil, nil
+	}
+	xs = make([]float64, n)
+	ys = make([]float64, n)
+	zs = make([]float64, n)
+	C.OGR_G_GetPoints(
+		geom.cval,
+		unsafe.Pointer(&xs[0]), C.sizeof_double,
+		unsafe.Pointer(&ys[0]), C.sizeof_double,
+		unsafe.Pointer(&zs[0]), C.sizeof_double)
+	return xs, ys, zs
+}
+
+// Fetch the coordinates of all the points of the geometry into buf, where the
+// coordinates of point i start at buf[i*stride]: x first, then y, then z when
+// stride is at least 3.  Returns the number of points copied.
+func (geom Geometry) PointsStrided(buf []float64, stride int) (int, error) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0, ErrClosed
+	}
+	if stride < 2 {
+		return 0, fmt.Errorf("invalid stride %d, must be at least 2", stride)
+	}
+	n := geom.PointCount()
+	if len(buf) < n*stride {
+		return 0, fmt.Errorf("buffer of %d values too small for %d points", len(buf), n)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	var z unsafe.Pointer
+	if stride >= 3 {
+		z = unsafe.Pointer(&buf[2])
+	}
+	byteStride := C.int(stride * C.sizeof_double)
+	C.OGR_G_GetPoints(
+		geom.cval,
+		unsafe.Pointer(&buf[0]), byteStride,
+		unsafe.Pointer(&buf[1]), byteStride,
+		z, byteStride)
+	return n, nil
+}
+
+// Replace all the points of the geometry (point, line string or linear ring
+// only) at once.  zs may be nil to make the geometry 2D.
+func (geom Geometry) SetPoints(xs, ys, zs []float64) error {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ErrClosed
+	}
+	n := len(xs)
+	if len(ys) != n || (zs != nil && len(zs) != n) {
+		return fmt.Errorf("xs, ys and zs hold %d, %d and %d points", len(xs), len(ys), len(zs))
+	}
+	if n == 0 {
+		C.OGR_G_Empty(geom.cval)
+		return nil
+	}
+	var z unsafe.Pointer
+	if zs != nil {
+		z = unsafe.Pointer(&zs[0])
+	}
+	C.OGR_G_SetPoints(
+		geom.cval,
+		C.int(n),
+		unsafe.Pointer(&xs[0]), C.sizeof_double,
+		unsafe.Pointer(&ys[0]), C.sizeof_double,
+		z, C.sizeof_double)
+	return nil
+}
+
+// Replace all the points of the geometry (point, line string or linear ring
+// only) at once from buf, where the coordinates of point i start at
+// buf[i*stride]: x first, then y, then z when stride is at least 3.  The
+// length of buf must be a multiple of stride.
+func (geom Geometry) SetPointsStrided(buf []float64, stride int) error {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ErrClosed
+	}
+	if stride < 2 {
+		return fmt.Errorf("invalid stride %d, must be at least 2", stride)
+	}
+	if len(buf)%stride != 0 {
+		return fmt.Errorf("buffer of %d values is not a multiple of stride %d", len(buf), stride)
+	}
+	n := len(buf) / stride
+	if n == 0 {
+		C.OGR_G_Empty(geom.cval)
+		return nil
+	}
+	var z unsafe.Pointer
+	if stride >= 3 {
+		z = unsafe.Pointer(&buf[2])
+	}
+	byteStride := C.int(stride * C.sizeof_double)
+	C.OGR_G_SetPoints(
+		geom.cval,
+		C.int(n),
+		unsafe.Pointer(&buf[0]), byteStride,
+		unsafe.Pointer(&buf[1]), byteStride,
+		z, byteStride)
+	return nil
+}
+
 // Set the coordinates of a point in the geometry
 func (geom Geometry) SetPoint(index int, x, y, z float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_SetPoint(
 		geom.cval,
 		C.int(index),
@@ -515,48 +1225,148 @@ func (geom Geometry) SetPoint(index int, x, y, z float64) {
 
 // Set the coordinates of a point in the geometry, ignoring the 3rd dimension
 func (geom Geometry) SetPoint2D(index int, x, y float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_SetPoint_2D(geom.cval, C.int(index), C.double(x), C.double(y))
 }
 
+// Set the coordinates of a point in the geometry, ignoring the 3rd dimension
+// and making the geometry measured
+func (geom Geometry) SetPointM(index int, x, y, m float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_SetPointM(geom.cval, C.int(index), C.double(x), C.double(y), C.double(m))
+}
+
+// Set the coordinates of a point in the geometry, making the geometry 3D and
+// measured
+func (geom Geometry) SetPointZM(index int, x, y, z, m float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_SetPointZM(
+		geom.cval,
+		C.int(index),
+		C.double(x),
+		C.double(y),
+		C.double(z),
+		C.double(m))
+}
+
 // Add a new point to the geometry (line string or polygon only)
 func (geom Geometry) AddPoint(x, y, z float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_AddPoint(geom.cval, C.double(x), C.double(y), C.double(z))
 }
 
 // Add a new point to the geometry (line string or polygon only), ignoring the 3rd dimension
 func (geom Geometry) AddPoint2D(x, y float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
 	C.OGR_G_AddPoint_2D(geom.cval, C.double(x), C.double(y))
 }
 
+// Add a new measured point to the geometry (line string or polygon only),
+// ignoring the 3rd dimension
+func (geom Geometry) AddPointM(x, y, m float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_AddPointM(geom.cval, C.double(x), C.double(y), C.double(m))
+}
+
+// Add a new measured 3D point to the geometry (line string or polygon only)
+func (geom Geometry) AddPointZM(x, y, z, m float64) {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return
+	}
+	C.OGR_G_AddPointZM(geom.cval, C.double(x), C.double(y), C.double(z), C.double(m))
+}
+
 // Fetch the number of elements in the geometry, or number of geometries in the container
 func (geom Geometry) GeometryCount() int {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return 0
+	}
 	count := C.OGR_G_GetGeometryCount(geom.cval)
 	return int(count)
 }
 
 // Fetch geometry from a geometry container
 func (geom Geometry) Geometry(index int) Geometry {
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}
+	}
 	newGeom := C.OGR_G_GetGeometryRef(geom.cval, C.int(index))
-	return Geometry{newGeom}
+	return Geometry{cval: newGeom, parent: geom.owner()}
+}
+
+// Parts returns an iterator over the geometries of a geometry container, or
+// the rings of a polygon.  The parts remain owned by the container.
+func (geom Geometry) Parts() iter.Seq[Geometry] {
+	return func(yield func(Geometry) bool) {
+		for i := 0; i < geom.GeometryCount(); i++ {
+			if !yield(geom.Geometry(i)) {
+				return
+			}
+		}
+	}
 }
 
 // Add a geometry to a geometry container
 func (geom Geometry) AddGeometry(other Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return ErrClosed
+	}
 	return C.OGR_G_AddGeometry(geom.cval, other.cval).Err()
 }
 
 // Add a geometry to a geometry container and assign ownership to that container
 func (geom Geometry) AddGeometryDirectly(other Geometry) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	if geom.closed() {
+		return ErrClosed
+	}
+	other.life.release()
 	return C.OGR_G_AddGeometryDirectly(geom.cval, other.cval).Err()
 }
 
 // Remove a geometry from the geometry container
 func (geom Geometry) RemoveGeometry(index int, delete bool) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return ErrClosed
+	}
 	return C.OGR_G_RemoveGeometry(geom.cval, C.int(index), BoolToCInt(delete)).Err()
 }
 
 // Build a polygon / ring from a set of lines
 func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (Geometry, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(geom)
+	if geom.closed() {
+		return Geometry{}, ErrClosed
+	}
 	var cErr C.OGRErr
 	newGeom := C.OGRBuildPolygonFromEdges(
 		geom.cval,
@@ -565,5 +1375,5 @@ func (geom Geometry) BuildPolygonFromEdges(autoClose bool, tolerance float64) (G
 		C.double(tolerance),
 		&cErr,
 	)
-	return Geometry{newGeom}, cErr.Err()
+	return newGeometry(newGeom), cErr.Err()
 }
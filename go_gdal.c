@@ -6,14 +6,153 @@
 #include "_cgo_export.h"
 
 #include <cpl_conv.h>
+#include <cpl_vsi.h>
+#include <math.h>
+#include <string.h>
+#include <sys/stat.h>
+
+#define GO_GDAL_NOT_SUPPORTED(version) \
+	CPLError(CE_Failure, CPLE_NotSupported, "%s() requires GDAL " version, __func__)
+
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 2, 0)
+int OGR_Fld_IsUnique(OGRFieldDefnH field) {
+	return FALSE;
+}
+
+void OGR_Fld_SetUnique(OGRFieldDefnH field, int unique) {
+	GO_GDAL_NOT_SUPPORTED("3.2");
+}
+
+const char *OGR_Fld_GetAlternativeNameRef(OGRFieldDefnH field) {
+	return "";
+}
+
+void OGR_Fld_SetAlternativeName(OGRFieldDefnH field, const char *name) {
+	GO_GDAL_NOT_SUPPORTED("3.2");
+}
+#endif
+
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 3, 0)
+// no field domain can be created or fetched, so that the accessors are never
+// called on a valid domain
+OGRFieldDomainH OGR_CodedFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const OGRCodedValue *enumeration
+) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+	return NULL;
+}
+
+OGRFieldDomainH OGR_RangeFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const OGRField *min, bool minIsInclusive,
+	const OGRField *max, bool maxIsInclusive
+) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+	return NULL;
+}
+
+OGRFieldDomainH OGR_GlobFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const char *glob
+) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+	return NULL;
+}
+
+void OGR_FldDomain_Destroy(OGRFieldDomainH domain) {
+}
+
+const char *OGR_FldDomain_GetName(OGRFieldDomainH domain) {
+	return "";
+}
+
+const char *OGR_FldDomain_GetDescription(OGRFieldDomainH domain) {
+	return "";
+}
+
+OGRFieldDomainType OGR_FldDomain_GetDomainType(OGRFieldDomainH domain) {
+	return OFDT_CODED;
+}
+
+OGRFieldType OGR_FldDomain_GetFieldType(OGRFieldDomainH domain) {
+	return OFTString;
+}
+
+OGRFieldSubType OGR_FldDomain_GetFieldSubType(OGRFieldDomainH domain) {
+	return OFSTNone;
+}
+
+const OGRCodedValue *OGR_CodedFldDomain_GetEnumeration(OGRFieldDomainH domain) {
+	return NULL;
+}
+
+const OGRField *OGR_RangeFldDomain_GetMin(OGRFieldDomainH domain, bool *isInclusive) {
+	return NULL;
+}
+
+const OGRField *OGR_RangeFldDomain_GetMax(OGRFieldDomainH domain, bool *isInclusive) {
+	return NULL;
+}
+
+const char *OGR_GlobFldDomain_GetGlob(OGRFieldDomainH domain) {
+	return "";
+}
+
+OGRFieldDomainH GDALDatasetGetFieldDomain(GDALDatasetH ds, const char *name) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+	return NULL;
+}
+
+bool GDALDatasetAddFieldDomain(GDALDatasetH ds, OGRFieldDomainH domain, char **reason) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+	return false;
+}
+
+const char *OGR_Fld_GetDomainName(OGRFieldDefnH field) {
+	return "";
+}
+
+void OGR_Fld_SetDomainName(OGRFieldDefnH field, const char *name) {
+	GO_GDAL_NOT_SUPPORTED("3.3");
+}
+#endif
+
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 5, 0)
+char **GDALDatasetGetFieldDomainNames(GDALDatasetH ds, CSLConstList options) {
+	return NULL;
+}
+
+bool GDALDatasetDeleteFieldDomain(GDALDatasetH ds, const char *name, char **reason) {
+	GO_GDAL_NOT_SUPPORTED("3.5");
+	return false;
+}
+
+bool GDALDatasetUpdateFieldDomain(GDALDatasetH ds, OGRFieldDomainH domain, char **reason) {
+	GO_GDAL_NOT_SUPPORTED("3.5");
+	return false;
+}
+#endif
+
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 7, 0)
+const char *OGR_Fld_GetComment(OGRFieldDefnH field) {
+	return "";
+}
+
+void OGR_Fld_SetComment(OGRFieldDefnH field, const char *comment) {
+	GO_GDAL_NOT_SUPPORTED("3.7");
+}
+#endif
 
 static int goGDALProgressFuncProxyB_(
 	double complete, 
 	const char *message, 
 	void *progressArg
 ) {
-	GoInterface* args = (GoInterface*)progressArg;
-	int returnVal = goGDALProgressFuncProxyA(complete, (char*)message, args);
+	int returnVal = goGDALProgressFuncProxyA(complete, (char*)message, progressArg);
 	return (int)returnVal;
 }
 
@@ -21,4 +160,206 @@ GDALProgressFunc goGDALProgressFuncProxyB() {
 	return goGDALProgressFuncProxyB_;
 }
 
+static void goGDALErrorHandlerProxyB_(
+	CPLErr errClass,
+	CPLErrorNum errNo,
+	const char *msg
+) {
+	uintptr_t handle = (uintptr_t)CPLGetErrorHandlerUserData();
+	goGDALErrorHandlerProxyA((int)errClass, (int)errNo, (char*)msg, handle);
+}
+
+void goGDALPushErrorHandler(uintptr_t handle) {
+	CPLPushErrorHandlerEx(goGDALErrorHandlerProxyB_, (void*)handle);
+}
+
+uintptr_t goGDALPopErrorHandler() {
+	// fetch the user data of the handler being popped in the same call, the
+	// handler stack is local to the OS thread.
+	uintptr_t handle = (uintptr_t)CPLGetErrorHandlerUserData();
+	CPLPopErrorHandler();
+	return handle;
+}
+
+void goGDALSetErrorHandler(uintptr_t handle) {
+	if (handle == 0) {
+		CPLSetErrorHandlerEx(CPLDefaultErrorHandler, NULL);
+		return;
+	}
+	CPLSetErrorHandlerEx(goGDALErrorHandlerProxyB_, (void*)handle);
+}
+
+static CPLErr goGDALContourWriterProxyB_(
+	double level,
+	int nPoints,
+	double *x,
+	double *y,
+	void *data
+) {
+	return (CPLErr)goGDALContourWriterProxyA(level, nPoints, x, y, (uintptr_t)data);
+}
+
+GDALContourGeneratorH goGDALContourGeneratorCreate(
+	int width, int height,
+	int useNoData, double noData,
+	double interval, double base,
+	uintptr_t handle
+) {
+	return GDAL_CG_Create(
+		width, height, useNoData, noData, interval, base,
+		goGDALContourWriterProxyB_, (void*)handle
+	);
+}
+
+void goGDALSetGridOptionsSize(void *options, size_t size) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
+	// nSizeOfStructure is the first member of all the option structures
+	*(size_t*)options = size;
+#endif
+}
+
+CPLErr goGDALClose(GDALDatasetH ds) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
+	return GDALClose(ds);
+#else
+	// GDALClose() returns void before GDAL 3.7, the error of the flush being
+	// only recorded by CPLError()
+	GDALClose(ds);
+	return CE_None;
+#endif
+}
+
+char *goGDALVectorInfo(GDALDatasetH ds, char **options) {
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
+	GDALVectorInfoOptions *opts = GDALVectorInfoOptionsNew(options, NULL);
+	if (opts == NULL) {
+		return NULL;
+	}
+	char *info = GDALVectorInfo(ds, opts);
+	GDALVectorInfoOptionsFree(opts);
+	return info;
+#else
+	CPLError(CE_Failure, CPLE_NotSupported, "GDALVectorInfo() requires GDAL 3.7");
+	return NULL;
+#endif
+}
+
+static void goGDALSetRawField(OGRField *field, OGRFieldType fieldType, double value) {
+	switch (fieldType) {
+	case OFTInteger:
+		field->Integer = (int)value;
+		break;
+	case OFTInteger64:
+		field->Integer64 = (GIntBig)value;
+		break;
+	default:
+		field->Real = value;
+	}
+}
 
+static double goGDALRawFieldValue(const OGRField *field, OGRFieldType fieldType, double unset) {
+	if (field == NULL || OGR_RawField_IsUnset(field)) {
+		return unset;
+	}
+	switch (fieldType) {
+	case OFTInteger:
+		return field->Integer;
+	case OFTInteger64:
+		return (double)field->Integer64;
+	default:
+		return field->Real;
+	}
+}
+
+OGRFieldDomainH goGDALRangeFldDomainCreate(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	double min, int minIsInclusive,
+	double max, int maxIsInclusive
+) {
+	OGRField minField, maxField;
+	goGDALSetRawField(&minField, fieldType, min);
+	goGDALSetRawField(&maxField, fieldType, max);
+	return OGR_RangeFldDomain_Create(
+		name, description, fieldType, fieldSubType,
+		isinf(min) ? NULL : &minField, minIsInclusive,
+		isinf(max) ? NULL : &maxField, maxIsInclusive
+	);
+}
+
+void goGDALRangeFldDomainGetBounds(
+	OGRFieldDomainH domain,
+	double *min, int *minIsInclusive,
+	double *max, int *maxIsInclusive
+) {
+	OGRFieldType fieldType = OGR_FldDomain_GetFieldType(domain);
+	bool inclusive = false;
+	*min = goGDALRawFieldValue(OGR_RangeFldDomain_GetMin(domain, &inclusive), fieldType, -INFINITY);
+	*minIsInclusive = inclusive;
+	*max = goGDALRawFieldValue(OGR_RangeFldDomain_GetMax(domain, &inclusive), fieldType, INFINITY);
+	*maxIsInclusive = inclusive;
+}
+
+static void *goGDALVSIOpenProxyB_(void *userData, const char *filename, const char *access) {
+	return (void*)goGDALVSIOpenProxyA((uintptr_t)userData, (char*)filename, (char*)access);
+}
+
+static size_t goGDALVSIReadProxyB_(void *file, void *buf, size_t size, size_t count) {
+	if (size == 0 || count == 0) {
+		return 0;
+	}
+	return goGDALVSIReadProxyA((uintptr_t)file, buf, size * count) / size;
+}
+
+static int goGDALVSISeekProxyB_(void *file, vsi_l_offset offset, int whence) {
+	return goGDALVSISeekProxyA((uintptr_t)file, (GIntBig)offset, whence);
+}
+
+static vsi_l_offset goGDALVSITellProxyB_(void *file) {
+	return (vsi_l_offset)goGDALVSITellProxyA((uintptr_t)file);
+}
+
+static int goGDALVSIEofProxyB_(void *file) {
+	return goGDALVSIEofProxyA((uintptr_t)file);
+}
+
+static int goGDALVSICloseProxyB_(void *file) {
+	return goGDALVSICloseProxyA((uintptr_t)file);
+}
+
+static int goGDALVSIStatProxyB_(void *userData, const char *filename, VSIStatBufL *buf, int flags) {
+	GIntBig size, mtime;
+	int isDir;
+	if (goGDALVSIStatProxyA((uintptr_t)userData, (char*)filename, &size, &mtime, &isDir) != 0) {
+		return -1;
+	}
+	memset(buf, 0, sizeof(VSIStatBufL));
+	buf->st_size = size;
+	buf->st_mtime = mtime;
+	buf->st_mode = isDir ? (S_IFDIR | 0555) : (S_IFREG | 0444);
+	return 0;
+}
+
+static char **goGDALVSIReadDirProxyB_(void *userData, const char *dirname, int maxFiles) {
+	return goGDALVSIReadDirProxyA((uintptr_t)userData, (char*)dirname, maxFiles);
+}
+
+int goGDALInstallVSIHandler(const char *prefix, uintptr_t handle) {
+	VSIFilesystemPluginCallbacksStruct *cb = VSIAllocFilesystemPluginCallbacksStruct();
+	cb->pUserData = (void*)handle;
+	cb->open = goGDALVSIOpenProxyB_;
+	cb->read = goGDALVSIReadProxyB_;
+	cb->seek = goGDALVSISeekProxyB_;
+	cb->tell = goGDALVSITellProxyB_;
+	cb->eof = goGDALVSIEofProxyB_;
+	cb->close = goGDALVSICloseProxyB_;
+	cb->stat = goGDALVSIStatProxyB_;
+	cb->read_dir = goGDALVSIReadDirProxyB_;
+	// read through a cache of 64kB blocks, so that the small reads of the
+	// drivers turn into fewer, larger range reads of the go file
+	cb->nBufferSize = 64 * 1024;
+	cb->nCacheSize = 16 * 1024 * 1024;
+	int ret = VSIInstallPluginHandler(prefix, cb);
+	VSIFreeFilesystemPluginCallbacksStruct(cb);
+	return ret;
+}
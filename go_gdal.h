@@ -5,15 +5,136 @@
 #ifndef GO_GDAL_H_
 #define GO_GDAL_H_
 
+#include <stdint.h>
+
 #include <gdal.h>
 #include <gdal_alg.h>
 #include <gdalwarper.h>
+#include <gdal_utils.h>
 #include <cpl_conv.h>
 #include <ogr_srs_api.h>
 
+// pixel data types added by later GDAL versions, GDT_Unknown when missing
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 5, 0)
+#define GO_GDT_INT64 GDT_Int64
+#define GO_GDT_UINT64 GDT_UInt64
+#else
+#define GO_GDT_INT64 GDT_Unknown
+#define GO_GDT_UINT64 GDT_Unknown
+#endif
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
+#define GO_GDT_INT8 GDT_Int8
+#else
+#define GO_GDT_INT8 GDT_Unknown
+#endif
+
+// field API of later GDAL versions, which go_gdal.c defines to fail with
+// CPLE_NotSupported when GDAL lacks it
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 2, 0)
+int OGR_Fld_IsUnique(OGRFieldDefnH field);
+void OGR_Fld_SetUnique(OGRFieldDefnH field, int unique);
+const char *OGR_Fld_GetAlternativeNameRef(OGRFieldDefnH field);
+void OGR_Fld_SetAlternativeName(OGRFieldDefnH field, const char *name);
+#endif
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 3, 0)
+#define OFSTUUID OFSTNone
+typedef struct OGRFieldDomainHS *OGRFieldDomainH;
+typedef struct {
+	char *pszCode;
+	char *pszValue;
+} OGRCodedValue;
+typedef enum {
+	OFDT_CODED,
+	OFDT_RANGE,
+	OFDT_GLOB
+} OGRFieldDomainType;
+OGRFieldDomainH OGR_CodedFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const OGRCodedValue *enumeration
+);
+OGRFieldDomainH OGR_RangeFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const OGRField *min, bool minIsInclusive,
+	const OGRField *max, bool maxIsInclusive
+);
+OGRFieldDomainH OGR_GlobFldDomain_Create(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	const char *glob
+);
+void OGR_FldDomain_Destroy(OGRFieldDomainH domain);
+const char *OGR_FldDomain_GetName(OGRFieldDomainH domain);
+const char *OGR_FldDomain_GetDescription(OGRFieldDomainH domain);
+OGRFieldDomainType OGR_FldDomain_GetDomainType(OGRFieldDomainH domain);
+OGRFieldType OGR_FldDomain_GetFieldType(OGRFieldDomainH domain);
+OGRFieldSubType OGR_FldDomain_GetFieldSubType(OGRFieldDomainH domain);
+const OGRCodedValue *OGR_CodedFldDomain_GetEnumeration(OGRFieldDomainH domain);
+const OGRField *OGR_RangeFldDomain_GetMin(OGRFieldDomainH domain, bool *isInclusive);
+const OGRField *OGR_RangeFldDomain_GetMax(OGRFieldDomainH domain, bool *isInclusive);
+const char *OGR_GlobFldDomain_GetGlob(OGRFieldDomainH domain);
+OGRFieldDomainH GDALDatasetGetFieldDomain(GDALDatasetH ds, const char *name);
+bool GDALDatasetAddFieldDomain(GDALDatasetH ds, OGRFieldDomainH domain, char **reason);
+const char *OGR_Fld_GetDomainName(OGRFieldDefnH field);
+void OGR_Fld_SetDomainName(OGRFieldDefnH field, const char *name);
+#endif
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 5, 0)
+char **GDALDatasetGetFieldDomainNames(GDALDatasetH ds, CSLConstList options);
+bool GDALDatasetDeleteFieldDomain(GDALDatasetH ds, const char *name, char **reason);
+bool GDALDatasetUpdateFieldDomain(GDALDatasetH ds, OGRFieldDomainH domain, char **reason);
+#endif
+#if GDAL_VERSION_NUM < GDAL_COMPUTE_VERSION(3, 7, 0)
+const char *OGR_Fld_GetComment(OGRFieldDefnH field);
+void OGR_Fld_SetComment(OGRFieldDefnH field, const char *comment);
+#endif
+
 // transform GDALProgressFunc to go func
 GDALProgressFunc goGDALProgressFuncProxyB();
 
+// install, remove and query go error handlers registered by cgo.Handle
+void goGDALPushErrorHandler(uintptr_t handle);
+uintptr_t goGDALPopErrorHandler();
+void goGDALSetErrorHandler(uintptr_t handle);
+
+// create a contour generator writing to the go ContourWriter registered by cgo.Handle
+GDALContourGeneratorH goGDALContourGeneratorCreate(
+	int width, int height,
+	int useNoData, double noData,
+	double interval, double base,
+	uintptr_t handle
+);
+
+// set the nSizeOfStructure member of the GDALGrid*Options, if GDAL has it
+void goGDALSetGridOptionsSize(void *options, size_t size);
+
+// close a dataset, returning CE_None before GDAL 3.7 where GDALClose() returns
+// nothing
+CPLErr goGDALClose(GDALDatasetH ds);
+
+// describe a vector dataset like ogrinfo, failing with CPLE_NotSupported
+// before GDAL 3.7
+char *goGDALVectorInfo(GDALDatasetH ds, char **options);
+
+// create a numeric range field domain, infinite bounds being unbounded
+OGRFieldDomainH goGDALRangeFldDomainCreate(
+	const char *name, const char *description,
+	OGRFieldType fieldType, OGRFieldSubType fieldSubType,
+	double min, int minIsInclusive,
+	double max, int maxIsInclusive
+);
+
+// fetch the bounds of a numeric range field domain, infinite when unbounded
+void goGDALRangeFldDomainGetBounds(
+	OGRFieldDomainH domain,
+	double *min, int *minIsInclusive,
+	double *max, int *maxIsInclusive
+);
+
+// install a virtual file system handler for prefix, reading through the go
+// fs.FS registered by cgo.Handle
+int goGDALInstallVSIHandler(const char *prefix, uintptr_t handle);
+
 #endif // GO_GDAL_H_
 
 
@@ -0,0 +1,301 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+/* ==================================================================== */
+/*      Conversion from and to image.Image                              */
+/* ==================================================================== */
+
+// ErrUnsupportedColorInterp is returned by Dataset.Image when the color
+// interpretation of the bands does not map onto a Go image type
+var ErrUnsupportedColorInterp = errors.New("unsupported color interpretation")
+
+// Read a region of the dataset as an image, the size of the window buffer.  A
+// zero window reads the whole raster.  The color interpretation of the bands
+// selects the type of the image:
+//
+//   - a palette band with an RGB or gray color table gives an *image.Paletted
+//   - a gray or undefined Byte band gives an *image.Gray, and a band of a wider
+//     data type an *image.Gray16, GDAL clamping the values
+//   - red, green and blue bands, and an optional alpha band, give an
+//     *image.RGBA
+//   - Y, Cb and Cr bands are converted to an opaque *image.RGBA
+func (dataset *Dataset) Image(win Window) (image.Image, error) {
+	if dataset.closed() {
+		return nil, ErrClosed
+	}
+	if win == (Window{}) {
+		win = Window{XSize: dataset.RasterXSize(), YSize: dataset.RasterYSize()}
+	}
+	if err := win.validate(); err != nil {
+		return nil, err
+	}
+	bufXSize, bufYSize := win.BufferSize()
+	rect := image.Rect(0, 0, bufXSize, bufYSize)
+
+	bands := make(map[ColorInterp]int)
+	for i := 1; i <= dataset.RasterCount(); i++ {
+		band, err := dataset.RasterBand(i)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := bands[band.ColorInterp()]; !ok {
+			bands[band.ColorInterp()] = i
+		}
+	}
+
+	switch {
+	case dataset.RasterCount() == 1:
+		band, err := dataset.RasterBand(1)
+		if err != nil {
+			return nil, err
+		}
+		return bandImage(band, win, rect)
+	case hasBands(bands, CI_RedBand, CI_GreenBand, CI_BlueBand):
+		img := image.NewRGBA(rect)
+		bandMap := []int{bands[CI_RedBand], bands[CI_GreenBand], bands[CI_BlueBand]}
+		if alpha, ok := bands[CI_AlphaBand]; ok {
+			bandMap = append(bandMap, alpha)
+		} else {
+			for i := 3; i < len(img.Pix); i += 4 {
+				img.Pix[i] = 0xff
+			}
+		}
+		if err := dataset.interleavedIO(Read, win, img.Pix, img.Stride, bandMap); err != nil {
+			return nil, err
+		}
+		if len(bandMap) == 4 {
+			// image.RGBA holds alpha-premultiplied colors
+			for i := 0; i < len(img.Pix); i += 4 {
+				a := uint16(img.Pix[i+3])
+				for j := i; j < i+3; j++ {
+					img.Pix[j] = uint8(uint16(img.Pix[j]) * a / 0xff)
+				}
+			}
+		}
+		return img, nil
+	case hasBands(bands, CI_YCbCr_YBand, CI_YCbCr_CbBand, CI_YCbCr_CrBand):
+		img := image.NewRGBA(rect)
+		bandMap := []int{bands[CI_YCbCr_YBand], bands[CI_YCbCr_CbBand], bands[CI_YCbCr_CrBand]}
+		if err := dataset.interleavedIO(Read, win, img.Pix, img.Stride, bandMap); err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(img.Pix); i += 4 {
+			p := img.Pix[i : i+4 : i+4]
+			p[0], p[1], p[2] = color.YCbCrToRGB(p[0], p[1], p[2])
+			p[3] = 0xff
+		}
+		return img, nil
+	}
+	return nil, ErrUnsupportedColorInterp
+}
+
+// hasBands reports whether the dataset has bands of all the given color
+// interpretations
+func hasBands(bands map[ColorInterp]int, interps ...ColorInterp) bool {
+	for _, interp := range interps {
+		if _, ok := bands[interp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// bandImage reads a single band image
+func bandImage(band *RasterBand, win Window, rect image.Rectangle) (image.Image, error) {
+	switch band.ColorInterp() {
+	case CI_PaletteIndex:
+		ct := band.ColorTable()
+		if ct == nil {
+			return nil, fmt.Errorf("%w: palette band without color table", ErrUnsupportedColorInterp)
+		}
+		palette, err := colorTablePalette(*ct)
+		if err != nil {
+			return nil, err
+		}
+		img := image.NewPaletted(rect, palette)
+		if err := ReadWindowInto(band, win, img.Pix); err != nil {
+			return nil, err
+		}
+		return img, nil
+	case CI_GrayIndex, CI_Undefined:
+		if band.RasterDataType() == Byte {
+			img := image.NewGray(rect)
+			if err := ReadWindowInto(band, win, img.Pix); err != nil {
+				return nil, err
+			}
+			return img, nil
+		}
+		values, err := ReadWindow[uint16](band, win)
+		if err != nil {
+			return nil, err
+		}
+		img := image.NewGray16(rect)
+		for i, v := range values {
+			img.Pix[2*i], img.Pix[2*i+1] = uint8(v>>8), uint8(v)
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("%w: single %s band", ErrUnsupportedColorInterp, band.ColorInterp().Name())
+}
+
+// colorTablePalette converts an RGB or gray color table to a palette of 256
+// colors, padded with transparent black
+func colorTablePalette(ct ColorTable) (color.Palette, error) {
+	interp := ct.PaletteInterpretation()
+	if interp != PI_RGB && interp != PI_Gray {
+		return nil, fmt.Errorf("%w: %s color table", ErrUnsupportedColorInterp, interp.Name())
+	}
+	palette := make(color.Palette, 256)
+	for i := range palette {
+		palette[i] = color.NRGBA{}
+		if i >= ct.EntryCount() {
+			continue
+		}
+		entry := ct.Entry(i).cval
+		if interp == PI_Gray {
+			palette[i] = color.NRGBA{uint8(entry.c1), uint8(entry.c1), uint8(entry.c1), 0xff}
+		} else {
+			palette[i] = color.NRGBA{uint8(entry.c1), uint8(entry.c2), uint8(entry.c3), uint8(entry.c4)}
+		}
+	}
+	return palette, nil
+}
+
+// interleavedIO reads or writes bands of the dataset from or to a buffer of 4
+// bytes per pixel, the bands of bandMap being the first bytes of each pixel
+func (dataset *Dataset) interleavedIO(rwFlag RWFlag, win Window, pix []uint8, stride int, bandMap []int) error {
+	bufXSize, bufYSize := win.BufferSize()
+	return dataset.IO(
+		rwFlag,
+		win.XOff, win.YOff, win.XSize, win.YSize,
+		pix,
+		bufXSize, bufYSize,
+		len(bandMap), bandMap,
+		4, stride, 1,
+	)
+}
+
+// Create a dataset holding an image with the given driver, such as "GTiff",
+// "PNG" or "MEM".  Gray images give a Byte or UInt16 gray band, paletted
+// images a palette band with an RGB color table, and other images red, green,
+// blue and, unless opaque, alpha Byte bands.  The image is written to an
+// in-memory dataset, copied to path unless the driver is MEM, so that drivers
+// that cannot create datasets, such as PNG, are supported too.
+func FromImage(img image.Image, driver, path string) (*Dataset, error) {
+	defer errorScope()()
+	drv, err := GetDriverByName(driver)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := GetDriverByName("MEM")
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("Error: empty image")
+	}
+	win := Window{XSize: w, YSize: h}
+
+	var ds *Dataset
+	switch img := img.(type) {
+	case *image.Gray:
+		ds = mem.Create("", w, h, 1, Byte, nil)
+		if ds == nil {
+			return nil, lastError(CE_Failure)
+		}
+		band, _ := ds.RasterBand(1)
+		band.SetColorInterp(CI_GrayIndex)
+		err = band.IO(Write, 0, 0, w, h, img.Pix[img.PixOffset(b.Min.X, b.Min.Y):], w, h, 1, img.Stride)
+	case *image.Gray16:
+		ds = mem.Create("", w, h, 1, UInt16, nil)
+		if ds == nil {
+			return nil, lastError(CE_Failure)
+		}
+		band, _ := ds.RasterBand(1)
+		band.SetColorInterp(CI_GrayIndex)
+		values := make([]uint16, 0, w*h)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				values = append(values, img.Gray16At(x, y).Y)
+			}
+		}
+		err = WriteWindow(band, win, values)
+	case *image.Paletted:
+		ds = mem.Create("", w, h, 1, Byte, nil)
+		if ds == nil {
+			return nil, lastError(CE_Failure)
+		}
+		band, _ := ds.RasterBand(1)
+		band.SetColorInterp(CI_PaletteIndex)
+		ct := CreateColorTable(PI_RGB)
+		defer ct.Destroy()
+		for i, c := range img.Palette {
+			nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+			entry := C.GDALColorEntry{
+				c1: C.short(nrgba.R),
+				c2: C.short(nrgba.G),
+				c3: C.short(nrgba.B),
+				c4: C.short(nrgba.A),
+			}
+			C.GDALSetColorEntry(ct.cval, C.int(i), &entry)
+		}
+		if err = band.SetColorTable(ct); err == nil {
+			err = band.IO(Write, 0, 0, w, h, img.Pix[img.PixOffset(b.Min.X, b.Min.Y):], w, h, 1, img.Stride)
+		}
+	default:
+		// GDAL alpha bands are not premultiplied
+		nrgba, ok := img.(*image.NRGBA)
+		if !ok {
+			nrgba = image.NewNRGBA(b)
+			draw.Draw(nrgba, b, img, b.Min, draw.Src)
+		}
+		bandMap := []int{1, 2, 3}
+		interps := []ColorInterp{CI_RedBand, CI_GreenBand, CI_BlueBand}
+		if !nrgba.Opaque() {
+			bandMap = append(bandMap, 4)
+			interps = append(interps, CI_AlphaBand)
+		}
+		ds = mem.Create("", w, h, len(bandMap), Byte, nil)
+		if ds == nil {
+			return nil, lastError(CE_Failure)
+		}
+		for i, interp := range interps {
+			band, _ := ds.RasterBand(i + 1)
+			band.SetColorInterp(interp)
+		}
+		err = ds.interleavedIO(Write, win, nrgba.Pix[nrgba.PixOffset(b.Min.X, b.Min.Y):], nrgba.Stride, bandMap)
+	}
+	if err != nil {
+		ds.Close()
+		return nil, err
+	}
+	if driver == "MEM" {
+		return ds, nil
+	}
+
+	defer ds.Close()
+	out := drv.CreateCopy(path, *ds, 0, nil, nil, nil)
+	if out == nil {
+		return nil, lastError(CE_Failure)
+	}
+	return out, nil
+}
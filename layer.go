@@ -10,35 +10,58 @@ package gdal
 #cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
 */
 import "C"
-import "unsafe"
+import (
+	"iter"
+	"runtime"
+	"unsafe"
+)
 
+// Layer is owned by its dataset or data source, which it keeps alive
 type Layer struct {
-	cval C.OGRLayerH
+	cval   C.OGRLayerH
+	parent *lifetime
 }
 
 // Return the layer name
 func (layer *Layer) Name() string {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ""
+	}
 	name := C.OGR_L_GetName(layer.cval)
 	return C.GoString(name)
 }
 
 // Return the layer geometry type
 func (layer *Layer) GeomType() GeometryType {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return 0
+	}
 	gt := C.OGR_L_GetGeomType(layer.cval)
 	return GeometryType(gt)
 }
 
 // Return the current spatial filter for this layer
 func (layer *Layer) SpatialFilter() *Geometry {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return nil
+	}
 	geom := C.OGR_L_GetSpatialFilter(layer.cval)
 	if geom == nil {
 		return nil
 	}
-	return &Geometry{geom}
+	return &Geometry{cval: geom, parent: layer.parent}
 }
 
 // Set a new spatial filter for this layer
 func (layer *Layer) SetSpatialFilter(filter *Geometry) {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(filter)
+	if layer.closed() {
+		return
+	}
 	if filter == nil {
 		C.OGR_L_SetSpatialFilter(layer.cval, nil)
 	} else {
@@ -48,14 +71,51 @@ func (layer *Layer) SetSpatialFilter(filter *Geometry) {
 
 // Set a new rectangular spatial filter for this layer
 func (layer *Layer) SetSpatialFilterRect(minX, minY, maxX, maxY float64) {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return
+	}
 	C.OGR_L_SetSpatialFilterRect(
 		layer.cval,
 		C.double(minX), C.double(minY), C.double(maxX), C.double(maxY),
 	)
 }
 
+// Set a new spatial filter on the indicated geometry field of this layer
+func (layer *Layer) SetSpatialFilterEx(index int, filter *Geometry) {
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(filter)
+	if layer.closed() {
+		return
+	}
+	if filter == nil {
+		C.OGR_L_SetSpatialFilterEx(layer.cval, C.int(index), nil)
+	} else {
+		C.OGR_L_SetSpatialFilterEx(layer.cval, C.int(index), filter.cval)
+	}
+}
+
+// Set a new rectangular spatial filter on the indicated geometry field of this
+// layer
+func (layer *Layer) SetSpatialFilterRectEx(index int, minX, minY, maxX, maxY float64) {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return
+	}
+	C.OGR_L_SetSpatialFilterRectEx(
+		layer.cval,
+		C.int(index),
+		C.double(minX), C.double(minY), C.double(maxX), C.double(maxY),
+	)
+}
+
 // Set a new attribute query filter
 func (layer *Layer) SetAttributeFilter(filter string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	cFilter := C.CString(filter)
 	defer C.free(unsafe.Pointer(cFilter))
 	return C.OGR_L_SetAttributeFilter(layer.cval, cFilter).Err()
@@ -63,70 +123,181 @@ func (layer *Layer) SetAttributeFilter(filter string) error {
 
 // Reset reading to start on the first feature
 func (layer *Layer) ResetReading() {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return
+	}
 	C.OGR_L_ResetReading(layer.cval)
 }
 
 // Fetch the next available feature from this layer
 func (layer *Layer) NextFeature() *Feature {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return nil
+	}
 	feature := C.OGR_L_GetNextFeature(layer.cval)
 	if feature == nil {
 		return nil
 	}
-	return &Feature{feature}
+	return newFeature(feature)
+}
+
+// Features returns an iterator over the features of the layer, reading from
+// the first one.  Each feature is destroyed once the loop body returns, unless
+// it was kept with Feature.Keep.  A read error is yielded along with an empty
+// feature, and ends the iteration.
+//
+//	for feature, err := range layer.Features() {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (layer *Layer) Features() iter.Seq2[Feature, error] {
+	return func(yield func(Feature, error) bool) {
+		defer errorScope()()
+		defer runtime.KeepAlive(layer)
+		if layer.closed() {
+			yield(Feature{}, ErrClosed)
+			return
+		}
+		C.OGR_L_ResetReading(layer.cval)
+		for {
+			// do not report errors left by the loop body
+			C.CPLErrorReset()
+			h := C.OGR_L_GetNextFeature(layer.cval)
+			if h == nil {
+				if ErrorClass(C.CPLGetLastErrorType()) >= CE_Failure {
+					yield(Feature{}, lastError(CE_Failure))
+				}
+				return
+			}
+			feature := newFeature(h)
+			more := yield(*feature, nil)
+			if !feature.life.kept {
+				feature.Destroy()
+			}
+			if !more {
+				return
+			}
+		}
+	}
 }
 
 // Move read cursor to the provided index
-func (layer *Layer) SetNextByIndex(index int) error {
+func (layer *Layer) SetNextByIndex(index int64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_SetNextByIndex(layer.cval, C.GIntBig(index)).Err()
 }
 
-// Fetch a feature by its index
-func (layer *Layer) Feature(index int) Feature {
-	feature := C.OGR_L_GetFeature(layer.cval, C.GIntBig(index))
-	return Feature{feature}
+// Fetch a feature by its identifier
+func (layer *Layer) Feature(fid int64) Feature {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return Feature{}
+	}
+	feature := C.OGR_L_GetFeature(layer.cval, C.GIntBig(fid))
+	if feature == nil {
+		return Feature{}
+	}
+	return *newFeature(feature)
 }
 
 // Rewrite the provided feature
 func (layer *Layer) SetFeature(feature *Feature) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(feature)
+	if layer.closed() || feature.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_SetFeature(layer.cval, feature.cval).Err()
 }
 
 // Create and write a new feature within a layer
 func (layer *Layer) CreateFeature(feature *Feature) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(feature)
+	if layer.closed() || feature.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_CreateFeature(layer.cval, feature.cval).Err()
 }
 
 // Delete indicated feature from layer
-func (layer *Layer) DeleteFeature(index int) error {
-	return C.OGR_L_DeleteFeature(layer.cval, C.GIntBig(index)).Err()
+func (layer *Layer) DeleteFeature(fid int64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
+	return C.OGR_L_DeleteFeature(layer.cval, C.GIntBig(fid)).Err()
 }
 
 // Fetch the schema information for this layer
 func (layer *Layer) Definition() FeatureDefinition {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return FeatureDefinition{}
+	}
 	defn := C.OGR_L_GetLayerDefn(layer.cval)
-	return FeatureDefinition{defn}
+	return FeatureDefinition{cval: defn, parent: layer.parent}
 }
 
 // Fetch the spatial reference system for this layer
 func (layer *Layer) SpatialRef() SpatialReference {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return SpatialReference{}
+	}
 	sr := C.OGR_L_GetSpatialRef(layer.cval)
-	return SpatialReference{sr}
+	return SpatialReference{cval: sr, parent: layer.parent}
 }
 
 // Fetch the feature count for this layer
-func (layer *Layer) FeatureCount(force bool) (count int, ok bool) {
-	count = int(C.OGR_L_GetFeatureCount(layer.cval, BoolToCInt(force)))
+func (layer *Layer) FeatureCount(force bool) (count int64, ok bool) {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return 0, false
+	}
+	count = int64(C.OGR_L_GetFeatureCount(layer.cval, BoolToCInt(force)))
 	return count, count != -1
 }
 
 // Fetch the extent of this layer
 func (layer *Layer) Extent(force bool) (env Envelope, err error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return Envelope{}, ErrClosed
+	}
 	err = C.OGR_L_GetExtent(layer.cval, &env.cval, BoolToCInt(force)).Err()
 	return
 }
 
+// Fetch the extent of the indicated geometry field of this layer
+func (layer *Layer) ExtentEx(index int, force bool) (env Envelope, err error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return Envelope{}, ErrClosed
+	}
+	err = C.OGR_L_GetExtentEx(layer.cval, C.int(index), &env.cval, BoolToCInt(force)).Err()
+	return
+}
+
 // Test if this layer supports the named capability
 func (layer *Layer) TestCapability(capability string) bool {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return false
+	}
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_L_TestCapability(layer.cval, cString)
@@ -135,63 +306,134 @@ func (layer *Layer) TestCapability(capability string) bool {
 
 // Create a new field on a layer
 func (layer *Layer) CreateField(fd FieldDefinition, approxOK bool) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(fd)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_CreateField(layer.cval, fd.cval, BoolToCInt(approxOK)).Err()
 }
 
+// Create a new geometry field on a layer
+func (layer *Layer) CreateGeomField(gfd GeometryFieldDefinition, approxOK bool) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(gfd)
+	if layer.closed() {
+		return ErrClosed
+	}
+	return C.OGR_L_CreateGeomField(layer.cval, gfd.cval, BoolToCInt(approxOK)).Err()
+}
+
 // Delete a field from the layer
 func (layer *Layer) DeleteField(index int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_DeleteField(layer.cval, C.int(index)).Err()
 }
 
 // Reorder all the fields of a layer
 func (layer *Layer) ReorderFields(layerMap []int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_ReorderFields(layer.cval, (*C.int)(unsafe.Pointer(&layerMap[0]))).Err()
 }
 
 // Reorder an existing field of a layer
 func (layer *Layer) ReorderField(oldIndex, newIndex int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_ReorderField(layer.cval, C.int(oldIndex), C.int(newIndex)).Err()
 }
 
 // Alter the definition of an existing field of a layer
 func (layer *Layer) AlterFieldDefn(index int, newDefn FieldDefinition, flags int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	defer runtime.KeepAlive(newDefn)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_AlterFieldDefn(layer.cval, C.int(index), newDefn.cval, C.int(flags)).Err()
 }
 
 // Begin a transation on data sources which support it
 func (layer *Layer) StartTransaction() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_StartTransaction(layer.cval).Err()
 }
 
 // Commit a transaction on data sources which support it
 func (layer *Layer) CommitTransaction() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_CommitTransaction(layer.cval).Err()
 }
 
 // Roll back the current transaction on data sources which support it
 func (layer *Layer) RollbackTransaction() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_RollbackTransaction(layer.cval).Err()
 }
 
 // Flush pending changes to the layer
 func (layer *Layer) Sync() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	return C.OGR_L_SyncToDisk(layer.cval).Err()
 }
 
 // Fetch the name of the FID column
 func (layer *Layer) FIDColumn() string {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ""
+	}
 	name := C.OGR_L_GetFIDColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Fetch the name of the geometry column
 func (layer *Layer) GeometryColumn() string {
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ""
+	}
 	name := C.OGR_L_GetGeometryColumn(layer.cval)
 	return C.GoString(name)
 }
 
 // Set which fields can be ignored when retrieving features from the layer
 func (layer *Layer) SetIgnoredFields(names []string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(layer)
+	if layer.closed() {
+		return ErrClosed
+	}
 	length := len(names)
 	cNames := make([]*C.char, length+1)
 	for i := 0; i < length; i++ {
@@ -39,7 +39,7 @@ func TestFeatureCount(t *testing.T) {
 func TestNextFeature(t *testing.T) {
 	lyr := getLayer(t)
 	n, _ := lyr.FeatureCount(true)
-	i := 0
+	var i int64
 	for feat := lyr.NextFeature(); feat != nil; feat = lyr.NextFeature() {
 		i++
 	}
@@ -107,7 +107,7 @@ func TestResetReading(t *testing.T) {
 	lyr.NextFeature()
 	lyr.ResetReading()
 	n, _ := lyr.FeatureCount(true)
-	i := 0
+	var i int64
 	for feat := lyr.NextFeature(); feat != nil; feat = lyr.NextFeature() {
 		i++
 	}
@@ -129,3 +129,143 @@ func TestIgnoreFields(t *testing.T) {
 		t.Log(feat.FieldIndex("EAS_ID"))
 	}
 }
+
+func TestRasterizeLayers(t *testing.T) {
+	lyr := getLayer(t)
+	wkt, err := lyr.SpatialRef().ToWKT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gt := [6]float64{478000, 100, 0, 4766000, 0, -100}
+
+	buf := make([]int32, 40*40)
+	err = RasterizeLayersBuf(buf, 40, 40, []Layer{*lyr}, wkt, gt, 0, []string{"ATTRIBUTE=EAS_ID"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[int32]bool)
+	for _, v := range buf {
+		if v != 0 {
+			ids[v] = true
+		}
+	}
+	if !ids[173] {
+		t.Errorf("feature 173 not burnt, got %v", ids)
+	}
+
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := drv.Create("", 40, 40, 1, Int32, nil)
+	defer ds.Close()
+	ds.SetProjection(wkt)
+	ds.SetGeoTransform(gt)
+	calls := 0
+	if err := ds.RasterizeLayers([]int{1}, []Layer{*lyr}, nil, []string{"ATTRIBUTE=EAS_ID"}, countProgress, &calls); err != nil {
+		t.Fatal(err)
+	}
+	band, _ := ds.RasterBand(1)
+	data, err := ReadWindow[int32](band, Window{XSize: 40, YSize: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range data {
+		if data[i] != buf[i] {
+			t.Fatalf("pixel %d: got %d, want %d", i, data[i], buf[i])
+		}
+	}
+	if calls == 0 {
+		t.Error("progress not reported")
+	}
+	if err := ds.RasterizeLayers([]int{1}, []Layer{{}}, nil, nil, nil, nil); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	if err := RasterizeLayersBuf(buf, 40, 40, []Layer{{}}, wkt, gt, 1, nil, nil, nil); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	geom, _ := CreateFromWKT("POINT (478100 4765900)", SpatialReference{})
+	geom.Destroy()
+	if err := ds.RasterizeGeometries([]int{1}, []Geometry{geom}, []float64{1}, nil, nil, nil); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	type poly struct {
+		Area  float64 `ogr:"AREA"`
+		EasID string  `ogr:"EAS_ID"`
+		Name  string  `ogr:"PRFEDEA"`
+		Other string
+	}
+	lyr := getLayer(t)
+	var polys []poly
+	if err := lyr.ScanAll(&polys); err != nil {
+		t.Fatal(err)
+	}
+	if len(polys) != 10 {
+		t.Fatalf("got %d features, want 10", len(polys))
+	}
+	if polys[0].EasID != "168" || polys[0].Name != "35043411" || polys[0].Area < 215229 || polys[0].Area > 215230 {
+		t.Errorf("invalid first feature: %+v", polys[0])
+	}
+
+	var bad []struct {
+		Name int `ogr:"PRFEDEA"`
+	}
+	if err := lyr.ScanAll(&bad); err == nil {
+		t.Error("string field scanned into an int")
+	}
+	var missing []struct {
+		Name string `ogr:"MISSING"`
+	}
+	if err := lyr.ScanAll(&missing); err == nil {
+		t.Error("missing field scanned")
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	lyr := getLayer(t)
+	n := 0
+	var kept Feature
+	for feature, err := range lyr.Features() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			kept = feature.Keep()
+		}
+		n++
+	}
+	if n != 10 {
+		t.Errorf("got %d features, want 10", n)
+	}
+	defer kept.Destroy()
+	if kept.FieldAsString(kept.FieldIndex("PRFEDEA")) != "35043411" {
+		t.Error("kept feature destroyed")
+	}
+
+	n = 0
+	for range lyr.Features() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("iteration not stopped")
+	}
+}
+
+func TestWriteClosedFeature(t *testing.T) {
+	lyr := getLayer(t)
+	if err := lyr.CreateFeature(nil); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	feat := lyr.NextFeature()
+	if feat == nil {
+		t.Fatal("no feature")
+	}
+	feat.Destroy()
+	if err := lyr.SetFeature(feat); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,196 @@
+package gdal
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// Handles owned by the caller (datasets, geometries, features, spatial
+// references, ...) are released by their Close method.  Close is idempotent,
+// and shared by all the copies of a handle: once one of them is closed, closing
+// any other one does nothing.  Handles borrowed from their parent, such as the
+// geometry returned by Feature.Geometry, are only detached by Close.
+//
+// Raster bands and layers keep their dataset alive, so a dataset cannot be
+// finalized while one of its bands or layers is still reachable.  Methods
+// and functions keep the handles they hand to GDAL alive with
+// runtime.KeepAlive until they return, so that no finalizer frees a handle
+// during a C call.
+//
+// The methods of a nil or closed handle, or of a handle borrowed from a closed
+// one, do nothing and return ErrClosed when they return an error, instead of
+// handing an invalid pointer to GDAL.
+
+// ErrClosed is returned by the methods of a nil or closed handle
+var ErrClosed = errors.New("handle is nil or closed")
+
+var (
+	_ io.Closer = (*Dataset)(nil)
+	_ io.Closer = (*DataSource)(nil)
+	_ io.Closer = (*Geometry)(nil)
+	_ io.Closer = (*Feature)(nil)
+	_ io.Closer = (*FieldDefinition)(nil)
+	_ io.Closer = (*GeometryFieldDefinition)(nil)
+	_ io.Closer = (*SpatialReference)(nil)
+	_ io.Closer = (*CoordinateTransform)(nil)
+	_ io.Closer = (*ColorTable)(nil)
+	_ io.Closer = (*RasterAttributeTable)(nil)
+	_ io.Closer = (*FieldDomain)(nil)
+	_ io.Closer = (*FeatureDefinition)(nil)
+	_ io.Closer = Transformer(nil)
+	_ io.Closer = (*ContourGenerator)(nil)
+)
+
+var finalizersEnabled atomic.Bool
+
+// EnableFinalizers sets whether the handles created from now on are released
+// by a finalizer once they become unreachable without having been closed.
+// Finalizers are disabled by default.
+//
+// Finalizers are a safety net against leaks, not a replacement for Close: they
+// run at an unspecified time after the handle became unreachable, on a
+// goroutine of their own, so the handles must not be shared with C code that
+// outlives their Go values.
+func EnableFinalizers(enable bool) {
+	finalizersEnabled.Store(enable)
+}
+
+// lifetime is shared by the copies of a handle owned by the caller.  It
+// records whether the handle was released and carries its finalizer.
+// Borrowed handles have no lifetime.
+type lifetime struct {
+	// released is also read and set by the finalizer, on a goroutine of its
+	// own
+	released atomic.Bool
+	// kept is set on the features yielded by Layer.Features that the caller
+	// took ownership of
+	kept bool
+	// cleanups run once the handle was freed, such as to remove the /vsimem/
+	// file a dataset was opened from
+	cleanups []func()
+}
+
+// newLifetime returns the lifetime of a newly owned handle, freed by free if
+// finalizers are enabled and the handle is never released.
+func newLifetime(free func()) *lifetime {
+	l := new(lifetime)
+	if finalizersEnabled.Load() {
+		runtime.SetFinalizer(l, func(l *lifetime) {
+			if l.released.CompareAndSwap(false, true) {
+				free()
+				l.runCleanups()
+			}
+		})
+	}
+	return l
+}
+
+// release marks the handle released and disarms its finalizer.  It returns
+// false if the handle was already released, and must not be freed again.
+// Handles without lifetime are always freed.
+func (l *lifetime) release() bool {
+	if l == nil {
+		return true
+	}
+	if !l.released.CompareAndSwap(false, true) {
+		return false
+	}
+	runtime.SetFinalizer(l, nil)
+	return true
+}
+
+// addCleanup registers a function to run once the handle was freed
+func (l *lifetime) addCleanup(cleanup func()) {
+	l.cleanups = append(l.cleanups, cleanup)
+}
+
+// runCleanups runs the functions registered with addCleanup, in reverse order
+func (l *lifetime) runCleanups() {
+	if l == nil {
+		return
+	}
+	for i := len(l.cleanups) - 1; i >= 0; i-- {
+		l.cleanups[i]()
+	}
+	l.cleanups = nil
+}
+
+// owned reports whether the handle is owned by the caller and not released yet
+func (l *lifetime) owned() bool {
+	return l != nil && !l.released.Load()
+}
+
+// closed reports whether the handle was released
+func (l *lifetime) closed() bool {
+	return l != nil && l.released.Load()
+}
+
+func (object *MajorObject) closed() bool {
+	return object == nil || object.cval == nil
+}
+
+func (dataset *Dataset) closed() bool {
+	return dataset == nil || dataset.cval == nil || dataset.life.closed() || dataset.parent.closed()
+}
+
+func (band *RasterBand) closed() bool {
+	return band == nil || band.cval == nil || band.parent.closed()
+}
+
+func (driver *Driver) closed() bool {
+	return driver == nil || driver.cval == nil
+}
+
+func (ct *ColorTable) closed() bool {
+	return ct == nil || ct.cval == nil || ct.life.closed() || ct.parent.closed()
+}
+
+func (rat *RasterAttributeTable) closed() bool {
+	return rat == nil || rat.cval == nil || rat.life.closed() || rat.parent.closed()
+}
+
+func (geom *Geometry) closed() bool {
+	return geom == nil || geom.cval == nil || geom.life.closed() || geom.parent.closed()
+}
+
+func (feature *Feature) closed() bool {
+	return feature == nil || feature.cval == nil || feature.life.closed()
+}
+
+func (layer *Layer) closed() bool {
+	return layer == nil || layer.cval == nil || layer.parent.closed()
+}
+
+func (fd *FeatureDefinition) closed() bool {
+	return fd == nil || fd.cval == nil || fd.life.closed() || fd.parent.closed()
+}
+
+func (fd *FieldDefinition) closed() bool {
+	return fd == nil || fd.cval == nil || fd.life.closed() || fd.parent.closed()
+}
+
+func (gfd *GeometryFieldDefinition) closed() bool {
+	return gfd == nil || gfd.cval == nil || gfd.life.closed() || gfd.parent.closed()
+}
+
+func (ds *DataSource) closed() bool {
+	return ds == nil || ds.cval == nil || ds.life.closed()
+}
+
+func (driver *OGRDriver) closed() bool {
+	return driver == nil || driver.cval == nil
+}
+
+func (sr *SpatialReference) closed() bool {
+	return sr == nil || sr.cval == nil || sr.life.closed() || sr.parent.closed()
+}
+
+func (ct *CoordinateTransform) closed() bool {
+	return ct == nil || ct.cval == nil || ct.life.closed()
+}
+
+func (domain *FieldDomain) closed() bool {
+	return domain == nil || domain.cval == nil || domain.life.closed() || domain.parent.closed()
+}
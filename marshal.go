@@ -0,0 +1,494 @@
+package gdal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Feature.Scan, Feature.Fill, Layer.ScanAll and Layer.WriteAll map the fields
+// of a feature to the exported fields of a struct tagged with the name of the
+// feature field:
+//
+//	type City struct {
+//		Name       string   `ogr:"NAME"`
+//		Population int      `ogr:"POP"`
+//		Area       *float64 `ogr:"AREA"` // nil when null or unset
+//	}
+//
+// Struct fields without an ogr tag, or tagged "-", are ignored.  The struct
+// field type must match the type of the feature field:
+//
+//	FT_Integer, FT_Integer64       any integer or floating point type
+//	FT_Real                        any floating point type
+//	FT_String                      string
+//	FT_IntegerList                 slice of an integer type
+//	FT_Integer64List               slice of an integer type
+//	FT_RealList                    slice of a floating point type
+//	FT_StringList                  []string
+//	FT_Binary                      []byte
+//	FT_Date, FT_Time, FT_DateTime  time.Time
+//
+// Any feature field may also be scanned into a string, formatted by GDAL.  A
+// pointer struct field is nil when the feature field is null or unset, and
+// makes the feature field null when nil.  Other struct fields are set to their
+// zero value.
+//
+// Scanning fails if an integer does not fit in its struct field, such as a
+// negative value into an unsigned type.  Likewise, filling fails if a value
+// does not fit in its integer feature field, such as a fraction or a uint64
+// beyond the int64 range.
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldBinding links a feature field to a struct field
+type fieldBinding struct {
+	name  string
+	field int
+	ftype FieldType
+	index []int
+}
+
+// bindFields returns the bindings of the tagged fields of the struct type t to
+// the fields of the feature definition.
+func bindFields(fd FeatureDefinition, t reflect.Type) ([]fieldBinding, error) {
+	var bindings []fieldBinding
+	for _, sf := range reflect.VisibleFields(t) {
+		name, ok := sf.Tag.Lookup("ogr")
+		if !ok || name == "-" || !sf.IsExported() {
+			continue
+		}
+		field := fd.FieldIndex(name)
+		if field < 0 {
+			return nil, fmt.Errorf("no field %q in feature definition", name)
+		}
+		ftype := fd.FieldDefinition(field).Type()
+		if err := checkFieldType(ftype, sf.Type); err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		bindings = append(bindings, fieldBinding{name, field, ftype, sf.Index})
+	}
+	return bindings, nil
+}
+
+// checkFieldType returns an error if values of a feature field of type ftype
+// cannot be stored in a struct field of type t.
+func checkFieldType(ftype FieldType, t reflect.Type) error {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ok := false
+	switch ftype {
+	case FT_Integer, FT_Integer64:
+		ok = isInteger(t.Kind()) || isFloat(t.Kind())
+	case FT_Real:
+		ok = isFloat(t.Kind())
+	case FT_String:
+		ok = t.Kind() == reflect.String
+	case FT_IntegerList, FT_Integer64List:
+		ok = t.Kind() == reflect.Slice && isInteger(t.Elem().Kind())
+	case FT_RealList:
+		ok = t.Kind() == reflect.Slice && isFloat(t.Elem().Kind())
+	case FT_StringList:
+		ok = t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String
+	case FT_Binary:
+		ok = t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+	case FT_Date, FT_Time, FT_DateTime:
+		ok = t == timeType
+	}
+	if !ok && t.Kind() != reflect.String {
+		return fmt.Errorf("%s values cannot be stored in %s", ftype.Name(), t)
+	}
+	return nil
+}
+
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// structValue returns the struct pointed to by ptr
+func structValue(ptr any, op string) (reflect.Value, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%s requires a non-nil pointer to a struct, not %T", op, ptr)
+	}
+	return v.Elem(), nil
+}
+
+// Scan copies the fields of the feature into the tagged fields of the struct
+// pointed to by dst.
+func (feature Feature) Scan(dst any) error {
+	if feature.closed() {
+		return ErrClosed
+	}
+	v, err := structValue(dst, "Scan")
+	if err != nil {
+		return err
+	}
+	bindings, err := bindFields(feature.Definition(), v.Type())
+	if err != nil {
+		return err
+	}
+	return feature.scan(v, bindings)
+}
+
+func (feature Feature) scan(v reflect.Value, bindings []fieldBinding) error {
+	for _, b := range bindings {
+		dst := v.FieldByIndex(b.index)
+		if !feature.IsFieldSetAndNotNull(b.field) {
+			dst.Set(reflect.Zero(dst.Type()))
+			continue
+		}
+		if dst.Kind() == reflect.Ptr {
+			dst.Set(reflect.New(dst.Type().Elem()))
+			dst = dst.Elem()
+		}
+		if err := feature.scanField(b, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setInteger stores value in the integer dst, and reports whether it fits
+func setInteger(dst reflect.Value, value int64) bool {
+	if dst.CanInt() {
+		if dst.OverflowInt(value) {
+			return false
+		}
+		dst.SetInt(value)
+		return true
+	}
+	if value < 0 || dst.OverflowUint(uint64(value)) {
+		return false
+	}
+	dst.SetUint(uint64(value))
+	return true
+}
+
+// overflow returns the error of a value of the field that does not fit in t
+func (b fieldBinding) overflow(value int64, t reflect.Type) error {
+	return fmt.Errorf("field %q: value %d overflows %s", b.name, value, t)
+}
+
+// integer returns the value of the integer or floating point src, checking
+// that it fits in an integer feature field of the given number of bits
+func (b fieldBinding) integer(src reflect.Value, bits int) (int64, error) {
+	var value int64
+	ok := true
+	switch {
+	case isFloat(src.Kind()):
+		f := src.Float()
+		value = int64(f)
+		ok = f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	case src.CanInt():
+		value = src.Int()
+	default:
+		u := src.Uint()
+		value = int64(u)
+		ok = u <= math.MaxInt64
+	}
+	if !ok || (bits == 32 && (value < math.MinInt32 || value > math.MaxInt32)) {
+		return 0, fmt.Errorf("field %q: value %v does not fit in a %d-bit integer", b.name, src, bits)
+	}
+	return value, nil
+}
+
+// scanField stores the value of a set field in dst
+func (feature Feature) scanField(b fieldBinding, dst reflect.Value) error {
+	if dst.Kind() == reflect.String {
+		dst.SetString(feature.FieldAsString(b.field))
+		return nil
+	}
+	switch b.ftype {
+	case FT_Integer, FT_Integer64, FT_Real:
+		switch {
+		case isFloat(dst.Kind()):
+			dst.SetFloat(feature.FieldAsFloat64(b.field))
+		default:
+			value := feature.FieldAsInteger64(b.field)
+			if !setInteger(dst, value) {
+				return b.overflow(value, dst.Type())
+			}
+		}
+	case FT_IntegerList, FT_Integer64List:
+		var values []int64
+		if b.ftype == FT_IntegerList {
+			for _, value := range feature.FieldAsIntegerList(b.field) {
+				values = append(values, int64(value))
+			}
+		} else {
+			values = feature.FieldAsInteger64List(b.field)
+		}
+		list := reflect.MakeSlice(dst.Type(), len(values), len(values))
+		for i, value := range values {
+			if !setInteger(list.Index(i), value) {
+				return b.overflow(value, list.Type().Elem())
+			}
+		}
+		dst.Set(list)
+	case FT_RealList:
+		values := feature.FieldAsFloat64List(b.field)
+		list := reflect.MakeSlice(dst.Type(), len(values), len(values))
+		for i, value := range values {
+			list.Index(i).SetFloat(value)
+		}
+		dst.Set(list)
+	case FT_StringList:
+		values := feature.FieldAsStringList(b.field)
+		list := reflect.MakeSlice(dst.Type(), len(values), len(values))
+		for i, value := range values {
+			list.Index(i).SetString(value)
+		}
+		dst.Set(list)
+	case FT_Binary:
+		dst.SetBytes(append([]byte(nil), feature.FieldAsBinary(b.field)...))
+	case FT_Date, FT_Time, FT_DateTime:
+		t, _ := feature.FieldAsDateTime(b.field)
+		dst.Set(reflect.ValueOf(t))
+	}
+	return nil
+}
+
+// Fill sets the fields of the feature from the tagged fields of the struct
+// pointed to by src.
+func (feature Feature) Fill(src any) error {
+	if feature.closed() {
+		return ErrClosed
+	}
+	v, err := structValue(src, "Fill")
+	if err != nil {
+		return err
+	}
+	bindings, err := bindFields(feature.Definition(), v.Type())
+	if err != nil {
+		return err
+	}
+	return feature.fill(v, bindings)
+}
+
+func (feature Feature) fill(v reflect.Value, bindings []fieldBinding) error {
+	for _, b := range bindings {
+		src := v.FieldByIndex(b.index)
+		if src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				feature.SetFieldNull(b.field)
+				continue
+			}
+			src = src.Elem()
+		}
+		if err := feature.fillField(b, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillField sets a field from the value of src
+func (feature Feature) fillField(b fieldBinding, src reflect.Value) error {
+	if src.Kind() == reflect.String {
+		feature.SetFieldString(b.field, src.String())
+		return nil
+	}
+	switch b.ftype {
+	case FT_Integer:
+		value, err := b.integer(src, 32)
+		if err != nil {
+			return err
+		}
+		feature.SetFieldInteger(b.field, int(value))
+	case FT_Integer64:
+		value, err := b.integer(src, 64)
+		if err != nil {
+			return err
+		}
+		feature.SetFieldInteger64(b.field, value)
+	case FT_Real:
+		feature.SetFieldFloat64(b.field, src.Float())
+	case FT_IntegerList:
+		values := make([]int, src.Len())
+		for i := range values {
+			value, err := b.integer(src.Index(i), 32)
+			if err != nil {
+				return err
+			}
+			values[i] = int(value)
+		}
+		feature.SetFieldIntegerList(b.field, values)
+	case FT_Integer64List:
+		values := make([]int64, src.Len())
+		for i := range values {
+			value, err := b.integer(src.Index(i), 64)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		}
+		feature.SetFieldInteger64List(b.field, values)
+	case FT_RealList:
+		values := make([]float64, src.Len())
+		for i := range values {
+			values[i] = src.Index(i).Float()
+		}
+		feature.SetFieldFloat64List(b.field, values)
+	case FT_StringList:
+		values := make([]string, src.Len())
+		for i := range values {
+			values[i] = src.Index(i).String()
+		}
+		feature.SetFieldStringList(b.field, values)
+	case FT_Binary:
+		feature.SetFieldBinary(b.field, src.Bytes())
+	case FT_Date, FT_Time, FT_DateTime:
+		feature.SetFieldDateTime(b.field, src.Interface().(time.Time))
+	}
+	return nil
+}
+
+// Values returns the fields of the feature by name.  The values are of type
+// int, int64, float64, string, []int, []int64, []float64, []string, []byte or
+// time.Time depending on the field type, or nil for null and unset fields.
+func (feature Feature) Values() map[string]any {
+	if feature.closed() {
+		return nil
+	}
+	fd := feature.Definition()
+	values := make(map[string]any, fd.FieldCount())
+	for i := 0; i < fd.FieldCount(); i++ {
+		defn := fd.FieldDefinition(i)
+		if !feature.IsFieldSetAndNotNull(i) {
+			values[defn.Name()] = nil
+			continue
+		}
+		var value any
+		switch defn.Type() {
+		case FT_Integer:
+			value = feature.FieldAsInteger(i)
+		case FT_Integer64:
+			value = feature.FieldAsInteger64(i)
+		case FT_Real:
+			value = feature.FieldAsFloat64(i)
+		case FT_IntegerList:
+			value = feature.FieldAsIntegerList(i)
+		case FT_Integer64List:
+			value = feature.FieldAsInteger64List(i)
+		case FT_RealList:
+			value = append([]float64(nil), feature.FieldAsFloat64List(i)...)
+		case FT_StringList:
+			value = feature.FieldAsStringList(i)
+		case FT_Binary:
+			value = append([]byte(nil), feature.FieldAsBinary(i)...)
+		case FT_Date, FT_Time, FT_DateTime:
+			value, _ = feature.FieldAsDateTime(i)
+		default:
+			value = feature.FieldAsString(i)
+		}
+		values[defn.Name()] = value
+	}
+	return values
+}
+
+// sliceElem returns the struct type of the elements of a slice of structs or
+// of pointers to structs, and whether the elements are pointers.
+func sliceElem(t reflect.Type, op string) (reflect.Type, bool, error) {
+	elem := t.Elem()
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, false, fmt.Errorf("%s requires a slice of structs, not %s", op, t)
+	}
+	return elem, isPtr, nil
+}
+
+// ScanAll reads all the features of the layer, from the start, into the
+// slice pointed to by dst, a slice of structs or of pointers to structs whose
+// fields are tagged as for Feature.Scan.  The features are appended to the
+// slice, including those read before a read error, which is returned.
+func (layer *Layer) ScanAll(dst any) error {
+	if layer.closed() {
+		return ErrClosed
+	}
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("ScanAll requires a non-nil pointer to a slice, not %T", dst)
+	}
+	slice := v.Elem()
+	elem, isPtr, err := sliceElem(slice.Type(), "ScanAll")
+	if err != nil {
+		return err
+	}
+	bindings, err := bindFields(layer.Definition(), elem)
+	if err != nil {
+		return err
+	}
+
+	for feature, err := range layer.Features() {
+		if err != nil {
+			return err
+		}
+		item := reflect.New(elem)
+		if err := feature.scan(item.Elem(), bindings); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, item))
+		} else {
+			slice.Set(reflect.Append(slice, item.Elem()))
+		}
+	}
+	return nil
+}
+
+// WriteAll creates a feature in the layer for every element of src, a slice
+// of structs or of pointers to structs whose fields are tagged as for
+// Feature.Fill.  The features have no geometry.
+func (layer *Layer) WriteAll(src any) error {
+	if layer.closed() {
+		return ErrClosed
+	}
+	v := reflect.ValueOf(src)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("WriteAll requires a slice, not %T", src)
+	}
+	elem, isPtr, err := sliceElem(v.Type(), "WriteAll")
+	if err != nil {
+		return err
+	}
+	fd := layer.Definition()
+	bindings, err := bindFields(fd, elem)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		if isPtr {
+			if item.IsNil() {
+				return fmt.Errorf("WriteAll: element %d is nil", i)
+			}
+			item = item.Elem()
+		}
+		feature := fd.Create()
+		err := feature.fill(item, bindings)
+		if err == nil {
+			err = layer.CreateFeature(&feature)
+		}
+		feature.Destroy()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package gdal
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// memPinners holds the pinners of the buffers of the MEM datasets not closed
+// yet, so that a dataset that is never closed leaks its buffers instead of
+// having its pinner collected while still pinning them.
+var memPinners = struct {
+	sync.Mutex
+	pinners map[*lifetime]*runtime.Pinner
+}{pinners: make(map[*lifetime]*runtime.Pinner)}
+
+// NewMemDataset wraps Go buffers in a MEM dataset, without copying them, so
+// that GDAL algorithms such as Polygonize, SieveFilter, FillNoData,
+// ComputeProximity or Warp read and write the slices directly.  Each band
+// holds width*height pixels, row by row.  A zero geotransform or an empty
+// spatial reference leaves the dataset without one.
+//
+// The buffers are pinned until the dataset is closed, and must not be resized
+// meanwhile.  The dataset should be closed: the buffers of a dataset left for
+// the garbage collector with finalizers disabled stay pinned, and leak.
+func NewMemDataset[T Numeric](
+	width, height int,
+	bands [][]T,
+	gt [6]float64,
+	srs SpatialReference,
+) (*Dataset, error) {
+	defer errorScope()()
+	if width <= 0 || height <= 0 || len(bands) == 0 {
+		return nil, fmt.Errorf("Error: invalid MEM dataset of %dx%d pixels and %d bands", width, height, len(bands))
+	}
+	for i, band := range bands {
+		if len(band) != width*height {
+			return nil, fmt.Errorf("Error: band %d holds %d pixels, %dx%d required", i+1, len(band), width, height)
+		}
+	}
+	drv, err := GetDriverByName("MEM")
+	if err != nil {
+		return nil, err
+	}
+	dataType, err := dataTypeFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	ds := drv.Create("", width, height, 0, dataType, nil)
+	if ds == nil {
+		return nil, lastError(CE_Failure)
+	}
+
+	// GDAL keeps the address of the buffers, which the cgo rules only allow
+	// for pinned memory
+	pinner := new(runtime.Pinner)
+	life := ds.life
+	memPinners.Lock()
+	memPinners.pinners[life] = pinner
+	memPinners.Unlock()
+	life.addCleanup(func() {
+		memPinners.Lock()
+		delete(memPinners.pinners, life)
+		memPinners.Unlock()
+		pinner.Unpin()
+	})
+	for _, band := range bands {
+		p := unsafe.Pointer(&band[0])
+		pinner.Pin(p)
+		if err := ds.AddBand(dataType, []string{fmt.Sprintf("DATAPOINTER=%p", p)}); err != nil {
+			ds.Close()
+			return nil, err
+		}
+	}
+	if gt != ([6]float64{}) {
+		if err := ds.SetGeoTransform(gt); err != nil {
+			ds.Close()
+			return nil, err
+		}
+	}
+	if srs.cval != nil {
+		wkt, err := srs.ToWKT()
+		if err == nil {
+			err = ds.SetProjection(wkt)
+		}
+		if err != nil {
+			ds.Close()
+			return nil, err
+		}
+	}
+	return ds, nil
+}
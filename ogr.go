@@ -12,6 +12,9 @@ package gdal
 import "C"
 import (
 	"errors"
+	"fmt"
+	"iter"
+	"runtime"
 	"unsafe"
 )
 
@@ -32,6 +35,44 @@ var (
 	ErrOGRNonExistingFeature      = errors.New("non-existing feature")
 )
 
+// ogrSentinel returns the ErrOGR* error matching an OGRErr code.
+func ogrSentinel(code int) error {
+	switch code {
+	case C.OGRERR_NOT_ENOUGH_DATA:
+		return ErrOGRNotEnoughData
+	case C.OGRERR_NOT_ENOUGH_MEMORY:
+		return ErrOGRNotEnoughMemory
+	case C.OGRERR_UNSUPPORTED_GEOMETRY_TYPE:
+		return ErrOGRUnsupportedGeometryType
+	case C.OGRERR_UNSUPPORTED_OPERATION:
+		return ErrOGRUnsupportedOperation
+	case C.OGRERR_CORRUPT_DATA:
+		return ErrOGRCorruptData
+	case C.OGRERR_UNSUPPORTED_SRS:
+		return ErrOGRUnsupportedSRS
+	case C.OGRERR_INVALID_HANDLE:
+		return ErrOGRInvalidHandle
+	case C.OGRERR_NON_EXISTING_FEATURE:
+		return ErrOGRNonExistingFeature
+	}
+	return ErrOGRFailure
+}
+
+// ogrClassSentinel returns the generic error OGRErr codes were reported as
+// before the introduction of Error, so that existing errors.Is() checks
+// against those keep matching.
+func ogrClassSentinel(code int) error {
+	switch code {
+	case 1:
+		return ErrDebug
+	case 2:
+		return ErrWarning
+	case 3, 4:
+		return ErrFailure
+	}
+	return ErrIllegal
+}
+
 type Envelope struct {
 	cval C.OGREnvelope
 }
@@ -177,8 +218,30 @@ const (
 	FT_Date        = FieldType(C.OFTDate)
 	FT_Time        = FieldType(C.OFTTime)
 	FT_DateTime    = FieldType(C.OFTDateTime)
+
+	FT_Integer64     = FieldType(C.OFTInteger64)
+	FT_Integer64List = FieldType(C.OFTInteger64List)
+)
+
+// Field subtype, refining the type of the field
+type FieldSubType int
+
+const (
+	FST_None    = FieldSubType(C.OFSTNone)
+	FST_Boolean = FieldSubType(C.OFSTBoolean)
+	FST_Int16   = FieldSubType(C.OFSTInt16)
+	FST_Float32 = FieldSubType(C.OFSTFloat32)
+	FST_JSON    = FieldSubType(C.OFSTJSON)
+	// FST_UUID requires GDAL 3.3, and equals FST_None with older versions
+	FST_UUID = FieldSubType(C.OFSTUUID)
 )
 
+// Fetch human readable name for the field subtype
+func (st FieldSubType) Name() string {
+	name := C.OGR_GetFieldSubTypeName(C.OGRFieldSubType(st))
+	return C.GoString(name)
+}
+
 type Justification int
 
 const (
@@ -188,7 +251,9 @@ const (
 )
 
 type FieldDefinition struct {
-	cval C.OGRFieldDefnH
+	cval   C.OGRFieldDefnH
+	life   *lifetime
+	parent *lifetime
 }
 
 type Field struct {
@@ -200,22 +265,49 @@ func CreateFieldDefinition(name string, fieldType FieldType) FieldDefinition {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	fieldDef := C.OGR_Fld_Create(cName, C.OGRFieldType(fieldType))
-	return FieldDefinition{fieldDef}
+	return FieldDefinition{
+		cval: fieldDef,
+		life: newLifetime(func() { C.OGR_Fld_Destroy(fieldDef) }),
+	}
 }
 
 // Destroy the field definition
 func (fd FieldDefinition) Destroy() {
-	C.OGR_Fld_Destroy(fd.cval)
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	if fd.life.release() {
+		C.OGR_Fld_Destroy(fd.cval)
+	}
+}
+
+// Close destroys the field definition if it is owned by the caller
+func (fd *FieldDefinition) Close() error {
+	defer runtime.KeepAlive(fd)
+	if fd.cval != nil && fd.life.owned() && fd.life.release() {
+		C.OGR_Fld_Destroy(fd.cval)
+	}
+	fd.cval = nil
+	return nil
 }
 
 // Fetch the name of the field
 func (fd FieldDefinition) Name() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
 	name := C.OGR_Fld_GetNameRef(fd.cval)
 	return C.GoString(name)
 }
 
 // Set the name of the field
 func (fd FieldDefinition) SetName(name string) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	C.OGR_Fld_SetName(fd.cval, cName)
@@ -223,45 +315,77 @@ func (fd FieldDefinition) SetName(name string) {
 
 // Fetch the type of this field
 func (fd FieldDefinition) Type() FieldType {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	fType := C.OGR_Fld_GetType(fd.cval)
 	return FieldType(fType)
 }
 
 // Set the type of this field
 func (fd FieldDefinition) SetType(fType FieldType) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_Fld_SetType(fd.cval, C.OGRFieldType(fType))
 }
 
 // Fetch the justification for this field
 func (fd FieldDefinition) Justification() Justification {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	justify := C.OGR_Fld_GetJustify(fd.cval)
 	return Justification(justify)
 }
 
 // Set the justification for this field
 func (fd FieldDefinition) SetJustification(justify Justification) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_Fld_SetJustify(fd.cval, C.OGRJustification(justify))
 }
 
 // Fetch the formatting width for this field
 func (fd FieldDefinition) Width() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	width := C.OGR_Fld_GetWidth(fd.cval)
 	return int(width)
 }
 
 // Set the formatting width for this field
 func (fd FieldDefinition) SetWidth(width int) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_Fld_SetWidth(fd.cval, C.int(width))
 }
 
 // Fetch the precision for this field
 func (fd FieldDefinition) Precision() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	precision := C.OGR_Fld_GetPrecision(fd.cval)
 	return int(precision)
 }
 
 // Set the precision for this field
 func (fd FieldDefinition) SetPrecision(precision int) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_Fld_SetPrecision(fd.cval, C.int(precision))
 }
 
@@ -272,6 +396,10 @@ func (fd FieldDefinition) Set(
 	width, precision int,
 	justify Justification,
 ) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -287,27 +415,348 @@ func (fd FieldDefinition) Set(
 
 // Fetch whether this field should be ignored when fetching features
 func (fd FieldDefinition) IsIgnored() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
 	ignore := C.OGR_Fld_IsIgnored(fd.cval)
 	return ignore != 0
 }
 
 // Set whether this field should be ignored when fetching features
 func (fd FieldDefinition) SetIgnored(ignore bool) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_Fld_SetIgnored(fd.cval, BoolToCInt(ignore))
 }
 
+// Fetch the subtype of this field
+func (fd FieldDefinition) SubType() FieldSubType {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
+	subType := C.OGR_Fld_GetSubType(fd.cval)
+	return FieldSubType(subType)
+}
+
+// Set the subtype of this field, which must be compatible with its type
+func (fd FieldDefinition) SetSubType(subType FieldSubType) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	C.OGR_Fld_SetSubType(fd.cval, C.OGRFieldSubType(subType))
+}
+
+// Fetch whether this field can receive null values
+func (fd FieldDefinition) IsNullable() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
+	nullable := C.OGR_Fld_IsNullable(fd.cval)
+	return nullable != 0
+}
+
+// Set whether this field can receive null values.  Fields are nullable by
+// default, pass false for a NOT NULL constraint.
+func (fd FieldDefinition) SetNullable(nullable bool) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	C.OGR_Fld_SetNullable(fd.cval, BoolToCInt(nullable))
+}
+
+// Fetch whether this field has a unique constraint.  Unique constraints and
+// alternative names require GDAL 3.2.
+func (fd FieldDefinition) IsUnique() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
+	unique := C.OGR_Fld_IsUnique(fd.cval)
+	return unique != 0
+}
+
+// Set whether this field has a unique constraint
+func (fd FieldDefinition) SetUnique(unique bool) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	C.OGR_Fld_SetUnique(fd.cval, BoolToCInt(unique))
+}
+
+// Fetch the default value of this field, as an SQL literal, or "" if none
+func (fd FieldDefinition) Default() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
+	def := C.OGR_Fld_GetDefault(fd.cval)
+	return C.GoString(def)
+}
+
+// Set the default value of this field, as an SQL literal: a number, a quoted
+// string such as 'foo', a 'YYYY/MM/DD HH:MM:SS' date time, CURRENT_TIMESTAMP,
+// CURRENT_DATE or CURRENT_TIME.  An empty string removes the default value.
+func (fd FieldDefinition) SetDefault(def string) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	cDef, free := optionalCString(def)
+	defer free()
+	C.OGR_Fld_SetDefault(fd.cval, cDef)
+}
+
+// Fetch whether the default value is driver specific, and not one of the
+// standard forms accepted by SetDefault
+func (fd FieldDefinition) IsDefaultDriverSpecific() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
+	specific := C.OGR_Fld_IsDefaultDriverSpecific(fd.cval)
+	return specific != 0
+}
+
+// Fetch the alternative name, or alias, of this field
+func (fd FieldDefinition) AlternativeName() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
+	name := C.OGR_Fld_GetAlternativeNameRef(fd.cval)
+	return C.GoString(name)
+}
+
+// Set the alternative name, or alias, of this field
+func (fd FieldDefinition) SetAlternativeName(name string) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_Fld_SetAlternativeName(fd.cval, cName)
+}
+
+// Fetch the comment of this field.  Field comments require GDAL 3.7.
+func (fd FieldDefinition) Comment() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
+	comment := C.OGR_Fld_GetComment(fd.cval)
+	return C.GoString(comment)
+}
+
+// Set the comment of this field
+func (fd FieldDefinition) SetComment(comment string) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	cComment := C.CString(comment)
+	defer C.free(unsafe.Pointer(cComment))
+	C.OGR_Fld_SetComment(fd.cval, cComment)
+}
+
+// Fetch the name of the field domain of this field, or "" if none
+func (fd FieldDefinition) DomainName() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
+	name := C.OGR_Fld_GetDomainName(fd.cval)
+	return C.GoString(name)
+}
+
+// Set the name of the field domain of this field, which must be registered
+// in the dataset with Dataset.AddFieldDomain.  An empty string removes it.
+func (fd FieldDefinition) SetDomainName(name string) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_Fld_SetDomainName(fd.cval, cName)
+}
+
 // Fetch human readable name for the field type
 func (ft FieldType) Name() string {
 	name := C.OGR_GetFieldTypeName(C.OGRFieldType(ft))
 	return C.GoString(name)
 }
 
+/* -------------------------------------------------------------------- */
+/*      Geometry field definition functions                             */
+/* -------------------------------------------------------------------- */
+
+type GeometryFieldDefinition struct {
+	cval   C.OGRGeomFieldDefnH
+	life   *lifetime
+	parent *lifetime
+}
+
+// Create a new geometry field definition
+func CreateGeometryFieldDefinition(name string, geomType GeometryType) GeometryFieldDefinition {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	fieldDef := C.OGR_GFld_Create(cName, C.OGRwkbGeometryType(geomType))
+	return GeometryFieldDefinition{
+		cval: fieldDef,
+		life: newLifetime(func() { C.OGR_GFld_Destroy(fieldDef) }),
+	}
+}
+
+// Destroy the geometry field definition
+func (gfd GeometryFieldDefinition) Destroy() {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return
+	}
+	if gfd.life.release() {
+		C.OGR_GFld_Destroy(gfd.cval)
+	}
+}
+
+// Close destroys the geometry field definition if it is owned by the caller
+func (gfd *GeometryFieldDefinition) Close() error {
+	defer runtime.KeepAlive(gfd)
+	if gfd.cval != nil && gfd.life.owned() && gfd.life.release() {
+		C.OGR_GFld_Destroy(gfd.cval)
+	}
+	gfd.cval = nil
+	return nil
+}
+
+// Fetch the name of the geometry field
+func (gfd GeometryFieldDefinition) Name() string {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return ""
+	}
+	name := C.OGR_GFld_GetNameRef(gfd.cval)
+	return C.GoString(name)
+}
+
+// Set the name of the geometry field
+func (gfd GeometryFieldDefinition) SetName(name string) {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_GFld_SetName(gfd.cval, cName)
+}
+
+// Fetch the geometry type of the geometry field
+func (gfd GeometryFieldDefinition) Type() GeometryType {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return 0
+	}
+	gt := C.OGR_GFld_GetType(gfd.cval)
+	return GeometryType(gt)
+}
+
+// Set the geometry type of the geometry field
+func (gfd GeometryFieldDefinition) SetType(geomType GeometryType) {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return
+	}
+	C.OGR_GFld_SetType(gfd.cval, C.OGRwkbGeometryType(geomType))
+}
+
+// Fetch the spatial reference of the geometry field, which remains owned by
+// the geometry field definition
+func (gfd GeometryFieldDefinition) SpatialReference() SpatialReference {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return SpatialReference{}
+	}
+	sr := C.OGR_GFld_GetSpatialRef(gfd.cval)
+	parent := gfd.life
+	if parent == nil {
+		parent = gfd.parent
+	}
+	return SpatialReference{cval: sr, parent: parent}
+}
+
+// Set the spatial reference of the geometry field
+func (gfd GeometryFieldDefinition) SetSpatialReference(sr SpatialReference) {
+	defer runtime.KeepAlive(gfd)
+	defer runtime.KeepAlive(sr)
+	if gfd.closed() {
+		return
+	}
+	C.OGR_GFld_SetSpatialRef(gfd.cval, sr.cval)
+}
+
+// Fetch whether the geometry field can receive null values
+func (gfd GeometryFieldDefinition) IsNullable() bool {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return false
+	}
+	nullable := C.OGR_GFld_IsNullable(gfd.cval)
+	return nullable != 0
+}
+
+// Set whether the geometry field can receive null values
+func (gfd GeometryFieldDefinition) SetNullable(nullable bool) {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return
+	}
+	C.OGR_GFld_SetNullable(gfd.cval, BoolToCInt(nullable))
+}
+
+// Fetch whether the geometry field should be ignored when fetching features
+func (gfd GeometryFieldDefinition) IsIgnored() bool {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return false
+	}
+	ignore := C.OGR_GFld_IsIgnored(gfd.cval)
+	return ignore != 0
+}
+
+// Set whether the geometry field should be ignored when fetching features
+func (gfd GeometryFieldDefinition) SetIgnored(ignore bool) {
+	defer runtime.KeepAlive(gfd)
+	if gfd.closed() {
+		return
+	}
+	C.OGR_GFld_SetIgnored(gfd.cval, BoolToCInt(ignore))
+}
+
 /* -------------------------------------------------------------------- */
 /*      Feature definition functions                                    */
 /* -------------------------------------------------------------------- */
 
 type FeatureDefinition struct {
-	cval C.OGRFeatureDefnH
+	cval   C.OGRFeatureDefnH
+	life   *lifetime
+	parent *lifetime
+}
+
+// owner returns the lifetime of the feature definition, or of its parent if
+// borrowed
+func (fd FeatureDefinition) owner() *lifetime {
+	if fd.life != nil {
+		return fd.life
+	}
+	return fd.parent
 }
 
 // Create a new feature definition object
@@ -315,39 +764,84 @@ func CreateFeatureDefinition(name string) FeatureDefinition {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	fd := C.OGR_FD_Create(cName)
-	return FeatureDefinition{fd}
+	// the caller holds a reference, so that the definition outlives the
+	// features and layers using it until it is released
+	C.OGR_FD_Reference(fd)
+	return FeatureDefinition{
+		cval: fd,
+		life: newLifetime(func() { C.OGR_FD_Release(fd) }),
+	}
 }
 
 // Destroy a feature definition object
 func (fd FeatureDefinition) Destroy() {
-	C.OGR_FD_Destroy(fd.cval)
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	if fd.life.release() {
+		C.OGR_FD_Destroy(fd.cval)
+	}
 }
 
 // Drop a reference, and delete object if no references remain
 func (fd FeatureDefinition) Release() {
-	C.OGR_FD_Release(fd.cval)
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
+	if fd.life.release() {
+		C.OGR_FD_Release(fd.cval)
+	}
+}
+
+// Close drops the reference held by the caller on a feature definition it
+// created, deleting it if no layer or feature references it any longer
+func (fd *FeatureDefinition) Close() error {
+	defer runtime.KeepAlive(fd)
+	if fd.cval != nil && fd.life.owned() && fd.life.release() {
+		C.OGR_FD_Release(fd.cval)
+	}
+	fd.cval = nil
+	return nil
 }
 
 // Fetch the name of this feature definition
 func (fd FeatureDefinition) Name() string {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ""
+	}
 	name := C.OGR_FD_GetName(fd.cval)
 	return C.GoString(name)
 }
 
 // Fetch the number of fields in the feature definition
 func (fd FeatureDefinition) FieldCount() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	count := C.OGR_FD_GetFieldCount(fd.cval)
 	return int(count)
 }
 
 // Fetch the definition of the indicated field
 func (fd FeatureDefinition) FieldDefinition(index int) FieldDefinition {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return FieldDefinition{}
+	}
 	fieldDefn := C.OGR_FD_GetFieldDefn(fd.cval, C.int(index))
-	return FieldDefinition{fieldDefn}
+	return FieldDefinition{cval: fieldDefn, parent: fd.owner()}
 }
 
 // Fetch the index of the named field
 func (fd FeatureDefinition) FieldIndex(name string) int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return -1
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	index := C.OGR_FD_GetFieldIndex(fd.cval, cName)
@@ -356,61 +850,160 @@ func (fd FeatureDefinition) FieldIndex(name string) int {
 
 // Add a new field definition to this feature definition
 func (fd FeatureDefinition) AddFieldDefinition(fieldDefn FieldDefinition) {
+	defer runtime.KeepAlive(fd)
+	defer runtime.KeepAlive(fieldDefn)
+	if fd.closed() {
+		return
+	}
 	C.OGR_FD_AddFieldDefn(fd.cval, fieldDefn.cval)
 }
 
 // Delete a field definition from this feature definition
 func (fd FeatureDefinition) DeleteFieldDefinition(index int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ErrClosed
+	}
 	return C.OGR_FD_DeleteFieldDefn(fd.cval, C.int(index)).Err()
 }
 
 // Fetch the geometry base type of this feature definition
 func (fd FeatureDefinition) GeometryType() GeometryType {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	gt := C.OGR_FD_GetGeomType(fd.cval)
 	return GeometryType(gt)
 }
 
 // Set the geometry base type for this feature definition
 func (fd FeatureDefinition) SetGeometryType(geomType GeometryType) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_FD_SetGeomType(fd.cval, C.OGRwkbGeometryType(geomType))
 }
 
+// Fetch the number of geometry fields in the feature definition
+func (fd FeatureDefinition) GeometryFieldCount() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
+	count := C.OGR_FD_GetGeomFieldCount(fd.cval)
+	return int(count)
+}
+
+// Fetch the definition of the indicated geometry field
+func (fd FeatureDefinition) GeometryFieldDefinition(index int) GeometryFieldDefinition {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return GeometryFieldDefinition{}
+	}
+	fieldDefn := C.OGR_FD_GetGeomFieldDefn(fd.cval, C.int(index))
+	return GeometryFieldDefinition{cval: fieldDefn, parent: fd.owner()}
+}
+
+// Fetch the index of the named geometry field
+func (fd FeatureDefinition) GeometryFieldIndex(name string) int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return -1
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	index := C.OGR_FD_GetGeomFieldIndex(fd.cval, cName)
+	return int(index)
+}
+
+// Add a new geometry field to the feature definition, which copies it.  Use
+// Layer.CreateGeomField to add a geometry field to a layer.
+func (fd FeatureDefinition) AddGeometryField(gfd GeometryFieldDefinition) {
+	defer runtime.KeepAlive(fd)
+	defer runtime.KeepAlive(gfd)
+	if fd.closed() {
+		return
+	}
+	C.OGR_FD_AddGeomFieldDefn(fd.cval, gfd.cval)
+}
+
+// Delete a geometry field from the feature definition
+func (fd FeatureDefinition) DeleteGeometryField(index int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return ErrClosed
+	}
+	return C.OGR_FD_DeleteGeomFieldDefn(fd.cval, C.int(index)).Err()
+}
+
 // Fetch if the geometry can be ignored when fetching features
 func (fd FeatureDefinition) IsGeometryIgnored() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
 	isIgnored := C.OGR_FD_IsGeometryIgnored(fd.cval)
 	return isIgnored != 0
 }
 
 // Set whether the geometry can be ignored when fetching features
 func (fd FeatureDefinition) SetGeometryIgnored(val bool) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_FD_SetGeometryIgnored(fd.cval, BoolToCInt(val))
 }
 
 // Fetch if the style can be ignored when fetching features
 func (fd FeatureDefinition) IsStyleIgnored() bool {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return false
+	}
 	isIgnored := C.OGR_FD_IsStyleIgnored(fd.cval)
 	return isIgnored != 0
 }
 
 // Set whether the style can be ignored when fetching features
 func (fd FeatureDefinition) SetStyleIgnored(val bool) {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return
+	}
 	C.OGR_FD_SetStyleIgnored(fd.cval, BoolToCInt(val))
 }
 
 // Increment the reference count by one
 func (fd FeatureDefinition) Reference() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	count := C.OGR_FD_Reference(fd.cval)
 	return int(count)
 }
 
 // Decrement the reference count by one
 func (fd FeatureDefinition) Dereference() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	count := C.OGR_FD_Dereference(fd.cval)
 	return int(count)
 }
 
 // Fetch the current reference count
 func (fd FeatureDefinition) ReferenceCount() int {
+	defer runtime.KeepAlive(fd)
+	if fd.closed() {
+		return 0
+	}
 	count := C.OGR_FD_GetReferenceCount(fd.cval)
 	return int(count)
 }
@@ -421,26 +1014,52 @@ func (fd FeatureDefinition) ReferenceCount() int {
 
 type DataSource struct {
 	cval C.OGRDataSourceH
+	life *lifetime
+}
+
+// newDataSource returns a data source owned by the caller
+func newDataSource(ds C.OGRDataSourceH) DataSource {
+	if ds == nil {
+		return DataSource{}
+	}
+	return DataSource{
+		cval: ds,
+		life: newLifetime(func() { C.OGR_DS_Destroy(ds) }),
+	}
 }
 
 // Open a file / data source with one of the registered drivers
-func OpenDataSource(name string, update int) DataSource {
+func OpenDataSource(name string, update int) (DataSource, error) {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	ds := C.OGROpen(cName, C.int(update), nil)
-	return DataSource{ds}
+	if ds == nil {
+		return DataSource{}, fmt.Errorf("Error: data source '%s' open error", name)
+	}
+	return newDataSource(ds), nil
 }
 
 // Open a shared file / data source with one of the registered drivers
-func OpenSharedDataSource(name string, update int) DataSource {
+func OpenSharedDataSource(name string, update int) (DataSource, error) {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	ds := C.OGROpenShared(cName, C.int(update), nil)
-	return DataSource{ds}
+	if ds == nil {
+		return DataSource{}, fmt.Errorf("Error: data source '%s' open error", name)
+	}
+	return newDataSource(ds), nil
 }
 
 // Drop a reference to this datasource and destroy if reference is zero
 func (ds DataSource) Release() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return ErrClosed
+	}
+	if !ds.life.release() {
+		return nil
+	}
 	return C.OGRReleaseDataSource(ds.cval).Err()
 }
 
@@ -453,53 +1072,95 @@ func OpenDataSourceCount() int {
 // Return the i'th datasource opened
 func OpenDataSourceByIndex(index int) DataSource {
 	ds := C.OGRGetOpenDS(C.int(index))
-	return DataSource{ds}
+	return DataSource{cval: ds}
 }
 
 // Closes datasource and releases resources
 func (ds DataSource) Destroy() {
-	C.OGR_DS_Destroy(ds.cval)
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return
+	}
+	if ds.life.release() {
+		C.OGR_DS_Destroy(ds.cval)
+	}
+}
+
+// Close closes the data source if it is owned by the caller.  Closing a data
+// source that is already closed does nothing.
+func (ds *DataSource) Close() error {
+	defer runtime.KeepAlive(ds)
+	if ds.cval != nil && ds.life.owned() && ds.life.release() {
+		C.OGR_DS_Destroy(ds.cval)
+	}
+	ds.cval = nil
+	return nil
 }
 
 // Fetch the name of the data source
 func (ds DataSource) Name() string {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return ""
+	}
 	name := C.OGR_DS_GetName(ds.cval)
 	return C.GoString(name)
 }
 
 // Fetch the number of layers in this data source
 func (ds DataSource) LayerCount() int {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return 0
+	}
 	count := C.OGR_DS_GetLayerCount(ds.cval)
 	return int(count)
 }
 
 // Fetch a layer of this data source by index
 func (ds DataSource) LayerByIndex(index int) *Layer {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return nil
+	}
 	layer := C.OGR_DS_GetLayer(ds.cval, C.int(index))
 	if layer == nil {
 		return nil
 	}
-	return &Layer{layer}
+	return &Layer{cval: layer, parent: ds.life}
 }
 
 // Fetch a layer of this data source by name
 func (ds DataSource) LayerByName(name string) *Layer {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return nil
+	}
 	cString := C.CString(name)
 	defer C.free(unsafe.Pointer(cString))
 	layer := C.OGR_DS_GetLayerByName(ds.cval, cString)
 	if layer == nil {
 		return nil
 	}
-	return &Layer{layer}
+	return &Layer{cval: layer, parent: ds.life}
 }
 
 // Delete the layer from the data source
 func (ds DataSource) Delete(index int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return ErrClosed
+	}
 	return C.OGR_DS_DeleteLayer(ds.cval, C.int(index)).Err()
 }
 
 // Fetch the driver that the data source was opened with
 func (ds DataSource) Driver() OGRDriver {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return OGRDriver{}
+	}
 	driver := C.OGR_DS_GetDriver(ds.cval)
 	return OGRDriver{driver}
 }
@@ -510,7 +1171,13 @@ func (ds DataSource) CreateLayer(
 	sr SpatialReference,
 	geomType GeometryType,
 	options []string,
-) Layer {
+) (Layer, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(sr)
+	if ds.closed() {
+		return Layer{}, ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -529,7 +1196,10 @@ func (ds DataSource) CreateLayer(
 		C.OGRwkbGeometryType(geomType),
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	if layer == nil {
+		return Layer{}, lastError(CE_Failure)
+	}
+	return Layer{cval: layer, parent: ds.life}, nil
 }
 
 // Duplicate an existing layer
@@ -537,7 +1207,13 @@ func (ds DataSource) CopyLayer(
 	source Layer,
 	name string,
 	options []string,
-) Layer {
+) (Layer, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(source)
+	if ds.closed() {
+		return Layer{}, ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -555,11 +1231,18 @@ func (ds DataSource) CopyLayer(
 		cName,
 		(**C.char)(unsafe.Pointer(&opts[0])),
 	)
-	return Layer{layer}
+	if layer == nil {
+		return Layer{}, lastError(CE_Failure)
+	}
+	return Layer{cval: layer, parent: ds.life}, nil
 }
 
 // Test if the data source has the indicated capability
 func (ds DataSource) TestCapability(capability string) bool {
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return false
+	}
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_DS_TestCapability(ds.cval, cString)
@@ -568,22 +1251,37 @@ func (ds DataSource) TestCapability(capability string) bool {
 
 // Execute an SQL statement against the data source
 func (ds DataSource) ExecuteSQL(sql string, filter Geometry, dialect string) Layer {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(filter)
+	if ds.closed() {
+		return Layer{}
+	}
 	cSQL := C.CString(sql)
 	defer C.free(unsafe.Pointer(cSQL))
 	cDialect := C.CString(dialect)
 	defer C.free(unsafe.Pointer(cDialect))
 
 	layer := C.OGR_DS_ExecuteSQL(ds.cval, cSQL, filter.cval, cDialect)
-	return Layer{layer}
+	return Layer{cval: layer, parent: ds.life}
 }
 
 // Release the results of ExecuteSQL
 func (ds DataSource) ReleaseResultSet(layer Layer) {
+	defer runtime.KeepAlive(ds)
+	defer runtime.KeepAlive(layer)
+	if ds.closed() {
+		return
+	}
 	C.OGR_DS_ReleaseResultSet(ds.cval, layer.cval)
 }
 
 // Flush pending changes to the data source
 func (ds DataSource) Sync() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return ErrClosed
+	}
 	return C.OGR_DS_SyncToDisk(ds.cval).Err()
 }
 
@@ -597,20 +1295,29 @@ type OGRDriver struct {
 
 // Fetch name of driver (file format)
 func (driver OGRDriver) Name() string {
+	if driver.closed() {
+		return ""
+	}
 	name := C.OGR_Dr_GetName(driver.cval)
 	return C.GoString(name)
 }
 
 // Attempt to open file with this driver
 func (driver OGRDriver) Open(filename string, update int) (newDS DataSource, ok bool) {
+	if driver.closed() {
+		return DataSource{}, false
+	}
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 	ds := C.OGR_Dr_Open(driver.cval, cFilename, C.int(update))
-	return DataSource{ds}, ds != nil
+	return newDataSource(ds), ds != nil
 }
 
 // Test if this driver supports the named capability
 func (driver OGRDriver) TestCapability(capability string) bool {
+	if driver.closed() {
+		return false
+	}
 	cString := C.CString(capability)
 	defer C.free(unsafe.Pointer(cString))
 	val := C.OGR_Dr_TestCapability(driver.cval, cString)
@@ -619,6 +1326,9 @@ func (driver OGRDriver) TestCapability(capability string) bool {
 
 // Create a new data source based on this driver
 func (driver OGRDriver) Create(name string, options []string) (newDS DataSource, ok bool) {
+	if driver.closed() {
+		return DataSource{}, false
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -631,11 +1341,15 @@ func (driver OGRDriver) Create(name string, options []string) (newDS DataSource,
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
 	ds := C.OGR_Dr_CreateDataSource(driver.cval, cName, (**C.char)(unsafe.Pointer(&opts[0])))
-	return DataSource{ds}, ds != nil
+	return newDataSource(ds), ds != nil
 }
 
 // Create a new datasource with this driver by copying all layers of the existing datasource
 func (driver OGRDriver) Copy(source DataSource, name string, options []string) (newDS DataSource, ok bool) {
+	defer runtime.KeepAlive(source)
+	if driver.closed() {
+		return DataSource{}, false
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 
@@ -648,11 +1362,15 @@ func (driver OGRDriver) Copy(source DataSource, name string, options []string) (
 	opts[length] = (*C.char)(unsafe.Pointer(nil))
 
 	ds := C.OGR_Dr_CopyDataSource(driver.cval, source.cval, cName, (**C.char)(unsafe.Pointer(&opts[0])))
-	return DataSource{ds}, ds != nil
+	return newDataSource(ds), ds != nil
 }
 
 // Delete a data source
 func (driver OGRDriver) Delete(filename string) error {
+	defer errorScope()()
+	if driver.closed() {
+		return ErrClosed
+	}
 	cFilename := C.CString(filename)
 	defer C.free(unsafe.Pointer(cFilename))
 	return C.OGR_Dr_DeleteDataSource(driver.cval, cFilename).Err()
@@ -660,11 +1378,17 @@ func (driver OGRDriver) Delete(filename string) error {
 
 // Add a driver to the list of registered drivers
 func (driver OGRDriver) Register() {
+	if driver.closed() {
+		return
+	}
 	C.OGRRegisterDriver(driver.cval)
 }
 
 // Remove a driver from the list of registered drivers
 func (driver OGRDriver) Deregister() {
+	if driver.closed() {
+		return
+	}
 	C.OGRDeregisterDriver(driver.cval)
 }
 
@@ -680,6 +1404,17 @@ func OGRDriverByIndex(index int) OGRDriver {
 	return OGRDriver{driver}
 }
 
+// OGRDrivers returns an iterator over the registered drivers
+func OGRDrivers() iter.Seq[OGRDriver] {
+	return func(yield func(OGRDriver) bool) {
+		for i := 0; i < OGRDriverCount(); i++ {
+			if !yield(OGRDriverByIndex(i)) {
+				return
+			}
+		}
+	}
+}
+
 // Fetch the indicated driver by name
 func OGRDriverByName(name string) OGRDriver {
 	cName := C.CString(name)
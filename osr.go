@@ -12,11 +12,25 @@ package gdal
 import "C"
 import (
 	"reflect"
+	"runtime"
 	"unsafe"
 )
 
 type SpatialReference struct {
-	cval C.OGRSpatialReferenceH
+	cval   C.OGRSpatialReferenceH
+	life   *lifetime
+	parent *lifetime
+}
+
+// newSpatialReference returns a spatial reference owned by the caller
+func newSpatialReference(sr C.OGRSpatialReferenceH) SpatialReference {
+	if sr == nil {
+		return SpatialReference{}
+	}
+	return SpatialReference{
+		cval: sr,
+		life: newLifetime(func() { C.OSRRelease(sr) }),
+	}
 }
 
 // Create a new SpatialReference
@@ -24,11 +38,16 @@ func CreateSpatialReference(wkt string) SpatialReference {
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	sr := C.OSRNewSpatialReference(cString)
-	return SpatialReference{sr}
+	return newSpatialReference(sr)
 }
 
 // Initialize SRS based on WKT string
 func (sr SpatialReference) FromWKT(wkt string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cString := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromWkt(sr.cval, &cString).Err()
@@ -36,6 +55,11 @@ func (sr SpatialReference) FromWKT(wkt string) error {
 
 // Export coordinate system to WKT
 func (sr SpatialReference) ToWKT() (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", ErrClosed
+	}
 	var p *C.char
 	err := C.OSRExportToWkt(sr.cval, &p).Err()
 	wkt := C.GoString(p)
@@ -44,6 +68,11 @@ func (sr SpatialReference) ToWKT() (string, error) {
 
 // Export coordinate system to a nicely formatted WKT string
 func (sr SpatialReference) ToPrettyWKT(simplify bool) (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", ErrClosed
+	}
 	var p *C.char
 	err := C.OSRExportToPrettyWkt(
 		sr.cval, &p, BoolToCInt(simplify),
@@ -54,70 +83,145 @@ func (sr SpatialReference) ToPrettyWKT(simplify bool) (string, error) {
 
 // Initialize SRS based on EPSG code
 func (sr SpatialReference) FromEPSG(code int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRImportFromEPSG(sr.cval, C.int(code)).Err()
 }
 
 // Initialize SRS based on EPSG code, using EPSG lat/long ordering
 func (sr SpatialReference) FromEPSGA(code int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRImportFromEPSGA(sr.cval, C.int(code)).Err()
 }
 
 // Destroy the spatial reference
 func (sr SpatialReference) Destroy() {
-	C.OSRDestroySpatialReference(sr.cval)
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return
+	}
+	if sr.life.release() {
+		C.OSRDestroySpatialReference(sr.cval)
+	}
+}
+
+// Close drops the reference of the caller to the spatial reference if it owns
+// one, destroying it when no other reference remains.  Closing a spatial
+// reference that is already closed does nothing.
+func (sr *SpatialReference) Close() error {
+	defer runtime.KeepAlive(sr)
+	if sr.cval != nil && sr.life.owned() && sr.life.release() {
+		C.OSRRelease(sr.cval)
+	}
+	sr.cval = nil
+	return nil
 }
 
 // Make a duplicate of this spatial reference
 func (sr SpatialReference) Clone() SpatialReference {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return SpatialReference{}
+	}
 	newSR := C.OSRClone(sr.cval)
-	return SpatialReference{newSR}
+	return newSpatialReference(newSR)
 }
 
 // Make a duplicate of the GEOGCS node of this spatial reference
 func (sr SpatialReference) CloneGeogCS() SpatialReference {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return SpatialReference{}
+	}
 	newSR := C.OSRCloneGeogCS(sr.cval)
-	return SpatialReference{newSR}
+	return newSpatialReference(newSR)
 }
 
 // Increments the reference count by one, returning reference count
 func (sr SpatialReference) Reference() int {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0
+	}
 	count := C.OSRReference(sr.cval)
 	return int(count)
 }
 
 // Decrements the reference count by one, returning reference count
 func (sr SpatialReference) Dereference() int {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0
+	}
 	count := C.OSRDereference(sr.cval)
 	return int(count)
 }
 
 // Decrements the reference count by one and destroy if zero
 func (sr SpatialReference) Release() {
-	C.OSRRelease(sr.cval)
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return
+	}
+	if sr.life.release() {
+		C.OSRRelease(sr.cval)
+	}
 }
 
 // Validate spatial reference tokens
 func (sr SpatialReference) Validate() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRValidate(sr.cval).Err()
 }
 
 // Correct parameter ordering to match CT specification
 func (sr SpatialReference) FixupOrdering() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRFixupOrdering(sr.cval).Err()
 }
 
 // Fix up spatial reference as needed
 func (sr SpatialReference) Fixup() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRFixup(sr.cval).Err()
 }
 
 // Strip OGC CT parameters
 func (sr SpatialReference) StripCTParams() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRStripCTParms(sr.cval).Err()
 }
 
 // Import PROJ.4 coordinate string
 func (sr SpatialReference) FromProj4(input string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromProj4(sr.cval, cString).Err()
@@ -125,6 +229,11 @@ func (sr SpatialReference) FromProj4(input string) error {
 
 // Export coordinate system in PROJ.4 format
 func (sr SpatialReference) ToProj4() (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", ErrClosed
+	}
 	var p *C.char
 	err := C.OSRExportToProj4(sr.cval, &p).Err()
 	proj4 := C.GoString(p)
@@ -133,6 +242,11 @@ func (sr SpatialReference) ToProj4() (string, error) {
 
 // Import coordinate system from ESRI .prj formats
 func (sr SpatialReference) FromESRI(input string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cString := C.CString(input)
 	defer C.free(unsafe.Pointer(cString))
 	return C.OSRImportFromProj4(sr.cval, cString).Err()
@@ -140,6 +254,11 @@ func (sr SpatialReference) FromESRI(input string) error {
 
 // Import coordinate system from PCI projection definition
 func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cUnits := C.CString(units)
@@ -155,6 +274,11 @@ func (sr SpatialReference) FromPCI(proj, units string, params []float64) error {
 
 // Import coordinate system from USGS projection definition
 func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRImportFromUSGS(
 		sr.cval,
 		C.long(projsys),
@@ -166,6 +290,11 @@ func (sr SpatialReference) FromUSGS(projsys, zone int, params []float64, datum i
 
 // Import coordinate system from XML format (GML only currently)
 func (sr SpatialReference) FromXML(xml string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cXml := C.CString(xml)
 	defer C.free(unsafe.Pointer(cXml))
 	return C.OSRImportFromXML(sr.cval, cXml).Err()
@@ -173,6 +302,11 @@ func (sr SpatialReference) FromXML(xml string) error {
 
 // Import coordinate system from ERMapper projection definitions
 func (sr SpatialReference) FromERM(proj, datum, units string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cProj := C.CString(proj)
 	defer C.free(unsafe.Pointer(cProj))
 	cDatum := C.CString(datum)
@@ -185,6 +319,11 @@ func (sr SpatialReference) FromERM(proj, datum, units string) error {
 
 // Import coordinate system from a URL
 func (sr SpatialReference) FromURL(url string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
 	return C.OSRImportFromXML(sr.cval, cURL).Err()
@@ -192,6 +331,11 @@ func (sr SpatialReference) FromURL(url string) error {
 
 // Export coordinate system in PCI format
 func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", "", nil, ErrClosed
+	}
 	var p, u *C.char
 	err := C.OSRExportToPCI(
 		sr.cval, &p, &u, (**C.double)(unsafe.Pointer(&params[0])),
@@ -206,6 +350,11 @@ func (sr SpatialReference) ToPCI() (proj, units string, params []float64, errVal
 
 // Export coordinate system to USGS GCTP projection definition
 func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int, errVal error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, 0, nil, 0, ErrClosed
+	}
 	err := C.OSRExportToUSGS(
 		sr.cval,
 		(*C.long)(unsafe.Pointer(&proj)),
@@ -223,6 +372,11 @@ func (sr SpatialReference) ToUSGS() (proj, zone int, params []float64, datum int
 
 // Export coordinate system in XML format
 func (sr SpatialReference) ToXML() (xml string, errVal error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", ErrClosed
+	}
 	var x *C.char
 	err := C.OSRExportToXML(sr.cval, &x, nil).Err()
 	defer C.free(unsafe.Pointer(x))
@@ -231,6 +385,11 @@ func (sr SpatialReference) ToXML() (xml string, errVal error) {
 
 // Export coordinate system in Mapinfo style CoordSys format
 func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", ErrClosed
+	}
 	var x *C.char
 	err := C.OSRExportToMICoordSys(sr.cval, &x).Err()
 	defer C.free(unsafe.Pointer(x))
@@ -242,16 +401,30 @@ func (sr SpatialReference) ToMICoordSys() (output string, errVal error) {
 
 // Convert in place to ESRI WKT format
 func (sr SpatialReference) MorphToESRI() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRMorphToESRI(sr.cval).Err()
 }
 
 // Convert in place from ESRI WKT format
 func (sr SpatialReference) MorphFromESRI() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRMorphFromESRI(sr.cval).Err()
 }
 
 // Fetch indicated attribute of named node
 func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bool) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", false
+	}
 	cKey := C.CString(key)
 	defer C.free(unsafe.Pointer(cKey))
 	val := C.OSRGetAttrValue(sr.cval, cKey, C.int(child))
@@ -260,6 +433,11 @@ func (sr SpatialReference) AttrValue(key string, child int) (value string, ok bo
 
 // Set attribute value in spatial reference
 func (sr SpatialReference) SetAttrValue(path, value string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cPath := C.CString(path)
 	defer C.free(unsafe.Pointer(cPath))
 	cValue := C.CString(value)
@@ -269,6 +447,11 @@ func (sr SpatialReference) SetAttrValue(path, value string) error {
 
 // Set the angular units for the geographic coordinate system
 func (sr SpatialReference) SetAngularUnits(units string, radians float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cUnits := C.CString(units)
 	defer C.free(unsafe.Pointer(cUnits))
 	return C.OSRSetAngularUnits(sr.cval, cUnits, C.double(radians)).Err()
@@ -276,6 +459,10 @@ func (sr SpatialReference) SetAngularUnits(units string, radians float64) error
 
 // Fetch the angular units for the geographic coordinate system
 func (sr SpatialReference) AngularUnits() (string, float64) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", 0
+	}
 	var x *C.char
 	factor := C.OSRGetAngularUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -284,6 +471,11 @@ func (sr SpatialReference) AngularUnits() (string, float64) {
 
 // Set the linear units for the projection
 func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLinearUnits(sr.cval, cName, C.double(toMeters)).Err()
@@ -291,6 +483,11 @@ func (sr SpatialReference) SetLinearUnits(name string, toMeters float64) error {
 
 // Set the linear units for the target node
 func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cUnits := C.CString(units)
@@ -300,6 +497,11 @@ func (sr SpatialReference) SetTargetLinearUnits(target, units string, toMeters f
 
 // Set the linear units for the target node and update all existing linear parameters
 func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMeters float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLinearUnitsAndUpdateParameters(sr.cval, cName, C.double(toMeters)).Err()
@@ -307,6 +509,10 @@ func (sr SpatialReference) SetLinearUnitsAndUpdateParameters(name string, toMete
 
 // Fetch linear projection units
 func (sr SpatialReference) LinearUnits() (string, float64) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", 0
+	}
 	var x *C.char
 	factor := C.OSRGetLinearUnits(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -315,6 +521,10 @@ func (sr SpatialReference) LinearUnits() (string, float64) {
 
 // Fetch linear units for target
 func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", 0
+	}
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	var x *C.char
@@ -325,6 +535,10 @@ func (sr SpatialReference) TargetLinearUnits(target string) (string, float64) {
 
 // Fetch prime meridian information
 func (sr SpatialReference) PrimeMeridian() (string, float64) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return "", 0
+	}
 	var x *C.char
 	offset := C.OSRGetPrimeMeridian(sr.cval, &x)
 	defer C.free(unsafe.Pointer(x))
@@ -333,60 +547,104 @@ func (sr SpatialReference) PrimeMeridian() (string, float64) {
 
 // Return true if geographic coordinate system
 func (sr SpatialReference) IsGeographic() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsGeographic(sr.cval)
 	return val != 0
 }
 
 // Return true if local coordinate system
 func (sr SpatialReference) IsLocal() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsLocal(sr.cval)
 	return val != 0
 }
 
 // Return true if projected coordinate system
 func (sr SpatialReference) IsProjected() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsProjected(sr.cval)
 	return val != 0
 }
 
 // Return true if compound coordinate system
 func (sr SpatialReference) IsCompound() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsCompound(sr.cval)
 	return val != 0
 }
 
 // Return true if geocentric coordinate system
 func (sr SpatialReference) IsGeocentric() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsGeocentric(sr.cval)
 	return val != 0
 }
 
 // Return true if vertical coordinate system
 func (sr SpatialReference) IsVertical() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsVertical(sr.cval)
 	return val != 0
 }
 
 // Return true if the geographic coordinate systems match
 func (sr SpatialReference) IsSameGeographicCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsSameGeogCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the vertical coordinate systems match
 func (sr SpatialReference) IsSameVerticalCS(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsSameVertCS(sr.cval, other.cval)
 	return val != 0
 }
 
 // Return true if the coordinate systems describe the same system
 func (sr SpatialReference) IsSame(other SpatialReference) bool {
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSRIsSame(sr.cval, other.cval)
 	return val != 0
 }
 
 // Set the user visible local CS name
 func (sr SpatialReference) SetLocalCS(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetLocalCS(sr.cval, cName).Err()
@@ -394,6 +652,11 @@ func (sr SpatialReference) SetLocalCS(name string) error {
 
 // Set the user visible projected CS name
 func (sr SpatialReference) SetProjectedCS(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjCS(sr.cval, cName).Err()
@@ -401,6 +664,11 @@ func (sr SpatialReference) SetProjectedCS(name string) error {
 
 // Set the user visible geographic CS name
 func (sr SpatialReference) SetGeocentricCS(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetGeocCS(sr.cval, cName).Err()
@@ -408,6 +676,11 @@ func (sr SpatialReference) SetGeocentricCS(name string) error {
 
 // Set geographic CS based on well known name
 func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetWellKnownGeogCS(sr.cval, cName).Err()
@@ -415,6 +688,11 @@ func (sr SpatialReference) SetWellKnownGeographicCS(name string) error {
 
 // Set spatial reference from various text formats
 func (sr SpatialReference) SetFromUserInput(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetFromUserInput(sr.cval, cName).Err()
@@ -422,11 +700,22 @@ func (sr SpatialReference) SetFromUserInput(name string) error {
 
 // Copy geographic CS from another spatial reference
 func (sr SpatialReference) CopyGeographicCSFrom(other SpatialReference) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(other)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRCopyGeogCSFrom(sr.cval, other.cval).Err()
 }
 
 // Set the Bursa-Wolf conversion to WGS84
 func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetTOWGS84(
 		sr.cval,
 		C.double(dx),
@@ -441,6 +730,11 @@ func (sr SpatialReference) SetTOWGS84(dx, dy, dz, ex, ey, ez, ppm float64) error
 
 // Fetch the TOWGS84 parameters if available
 func (sr SpatialReference) TOWGS84() (coeff [7]float64, err error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return [7]float64{}, ErrClosed
+	}
 	err = C.OSRGetTOWGS84(sr.cval, (*C.double)(unsafe.Pointer(&coeff[0])), 7).Err()
 	return
 }
@@ -450,6 +744,13 @@ func (sr SpatialReference) SetCompoundCS(
 	name string,
 	horizontal, vertical SpatialReference,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	defer runtime.KeepAlive(horizontal)
+	defer runtime.KeepAlive(vertical)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetCompoundCS(sr.cval, cName, horizontal.cval, vertical.cval).Err()
@@ -464,6 +765,11 @@ func (sr SpatialReference) SetGeographicCS(
 	angularUnits string,
 	toRadians float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cGeogName := C.CString(geogName)
 	defer C.free(unsafe.Pointer(cGeogName))
 	cDatumName := C.CString(datumName)
@@ -490,6 +796,11 @@ func (sr SpatialReference) SetGeographicCS(
 
 // Set up the vertical coordinate system
 func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cCSName := C.CString(csName)
 	defer C.free(unsafe.Pointer(cCSName))
 	cDatumName := C.CString(datumName)
@@ -499,6 +810,11 @@ func (sr SpatialReference) SetVerticalCS(csName, datumName string, datumType int
 
 // Get spheroid semi-major axis
 func (sr SpatialReference) SemiMajorAxis() (float64, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, ErrClosed
+	}
 	var cErr C.OGRErr
 	axis := C.OSRGetSemiMajor(sr.cval, &cErr)
 	return float64(axis), cErr.Err()
@@ -506,6 +822,11 @@ func (sr SpatialReference) SemiMajorAxis() (float64, error) {
 
 // Get spheroid semi-minor axis
 func (sr SpatialReference) SemiMinorAxis() (float64, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, ErrClosed
+	}
 	var cErr C.OGRErr
 	axis := C.OSRGetSemiMinor(sr.cval, &cErr)
 	return float64(axis), cErr.Err()
@@ -513,6 +834,11 @@ func (sr SpatialReference) SemiMinorAxis() (float64, error) {
 
 // Get spheroid inverse flattening axis
 func (sr SpatialReference) InverseFlattening() (float64, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, ErrClosed
+	}
 	var cErr C.OGRErr
 	flat := C.OSRGetInvFlattening(sr.cval, &cErr)
 	return float64(flat), cErr.Err()
@@ -520,6 +846,11 @@ func (sr SpatialReference) InverseFlattening() (float64, error) {
 
 // Sets the authority for a node
 func (sr SpatialReference) SetAuthority(target, authority string, code int) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	cAuthority := C.CString(authority)
@@ -529,6 +860,10 @@ func (sr SpatialReference) SetAuthority(target, authority string, code int) erro
 
 // Get the authority code for a node
 func (sr SpatialReference) AuthorityCode(target string) string {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ""
+	}
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityCode(sr.cval, cTarget)
@@ -537,6 +872,10 @@ func (sr SpatialReference) AuthorityCode(target string) string {
 
 // Get the authority name for a node
 func (sr SpatialReference) AuthorityName(target string) string {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ""
+	}
 	cTarget := C.CString(target)
 	defer C.free(unsafe.Pointer(cTarget))
 	code := C.OSRGetAuthorityName(sr.cval, cTarget)
@@ -545,6 +884,11 @@ func (sr SpatialReference) AuthorityName(target string) string {
 
 // Set a projection by name
 func (sr SpatialReference) SetProjectionByName(name string) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjection(sr.cval, cName).Err()
@@ -552,6 +896,11 @@ func (sr SpatialReference) SetProjectionByName(name string) error {
 
 // Set a projection parameter value
 func (sr SpatialReference) SetProjectionParameter(name string, value float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetProjParm(sr.cval, cName, C.double(value)).Err()
@@ -559,6 +908,11 @@ func (sr SpatialReference) SetProjectionParameter(name string, value float64) er
 
 // Fetch a projection parameter value
 func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64) (float64, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	var cErr C.OGRErr
@@ -568,6 +922,11 @@ func (sr SpatialReference) ProjectionParameter(name string, defaultValue float64
 
 // Set a projection parameter with a normalized value
 func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetNormProjParm(sr.cval, cName, C.double(value)).Err()
@@ -577,6 +936,11 @@ func (sr SpatialReference) SetNormalizedProjectionParameter(name string, value f
 func (sr SpatialReference) NormalizedProjectionParameter(
 	name string, defaultValue float64,
 ) (float64, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	var cErr C.OGRErr
@@ -586,11 +950,20 @@ func (sr SpatialReference) NormalizedProjectionParameter(
 
 // Set UTM projection definition
 func (sr SpatialReference) SetUTM(zone int, north bool) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetUTM(sr.cval, C.int(zone), BoolToCInt(north)).Err()
 }
 
 // Get UTM zone information
 func (sr SpatialReference) UTMZone() (zone int, north bool) {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return 0, false
+	}
 	var northInt C.int
 	cZone := C.OSRGetUTMZone(sr.cval, &northInt)
 	return int(cZone), northInt != 0
@@ -598,6 +971,11 @@ func (sr SpatialReference) UTMZone() (zone int, north bool) {
 
 // Set State Plane projection definition
 func (sr SpatialReference) SetStatePlane(zone int, nad83 bool) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetStatePlane(sr.cval, C.int(zone), BoolToCInt(nad83)).Err()
 }
 
@@ -608,6 +986,11 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 	unitName string,
 	factor float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cUnitName := C.CString(unitName)
 	defer C.free(unsafe.Pointer(cUnitName))
 	return C.OSRSetStatePlaneWithUnits(
@@ -621,11 +1004,20 @@ func (sr SpatialReference) SetStatePlaneWithUnits(
 
 // Set EPSG authority info if possible
 func (sr SpatialReference) AutoIdentifyEPSG() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRAutoIdentifyEPSG(sr.cval).Err()
 }
 
 // Return true if EPSG feels this coordinate system should be treated as having lat/long coordinate ordering
 func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return false
+	}
 	val := C.OSREPSGTreatsAsLatLong(sr.cval)
 	return val != 0
 }
@@ -637,6 +1029,11 @@ func (sr SpatialReference) EPSGTreatsAsLatLong() bool {
 func (sr SpatialReference) SetACEA(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetACEA(
 		sr.cval,
 		C.double(stdp1),
@@ -650,6 +1047,11 @@ func (sr SpatialReference) SetACEA(
 
 // Set to Azimuthal Equidistant
 func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetAE(
 		sr.cval,
 		C.double(centerLat),
@@ -661,6 +1063,11 @@ func (sr SpatialReference) SetAE(centerLat, centerLong, falseEasting, falseNorth
 
 // Set to Bonne
 func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetBonne(
 		sr.cval,
 		C.double(standardParallel),
@@ -672,6 +1079,11 @@ func (sr SpatialReference) SetBonne(standardParallel, centralMeridian, falseEast
 
 // Set to Cylindrical Equal Area
 func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetCEA(
 		sr.cval,
 		C.double(stdp1),
@@ -683,6 +1095,11 @@ func (sr SpatialReference) SetCEA(stdp1, centralMeridian, falseEasting, falseNor
 
 // Set to Cassini-Soldner
 func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetCS(
 		sr.cval,
 		C.double(centerLat),
@@ -696,6 +1113,11 @@ func (sr SpatialReference) SetCS(centerLat, centerLong, falseEasting, falseNorth
 func (sr SpatialReference) SetEC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetEC(
 		sr.cval,
 		C.double(stdp1),
@@ -709,6 +1131,11 @@ func (sr SpatialReference) SetEC(
 
 // Set to Eckert I-VI
 func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetEckert(
 		sr.cval,
 		C.int(variation),
@@ -722,6 +1149,11 @@ func (sr SpatialReference) SetEckert(variation int, centralMeridian, falseEastin
 func (sr SpatialReference) SetEquirectangular(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetEquirectangular(
 		sr.cval,
 		C.double(centerLat),
@@ -735,6 +1167,11 @@ func (sr SpatialReference) SetEquirectangular(
 func (sr SpatialReference) SetEquirectangularGeneralized(
 	centerLat, centerLong, psuedoStdParallel, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetEquirectangular2(
 		sr.cval,
 		C.double(centerLat),
@@ -747,6 +1184,11 @@ func (sr SpatialReference) SetEquirectangularGeneralized(
 
 // Set to Gall Stereographic
 func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetGS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -757,6 +1199,11 @@ func (sr SpatialReference) SetGS(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Goode Homolosine
 func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetGH(
 		sr.cval,
 		C.double(centralMeridian),
@@ -767,6 +1214,11 @@ func (sr SpatialReference) SetGH(centralMeridian, falseEasting, falseNorthing fl
 
 // Set to Interrupted Goode Homolosine
 func (sr SpatialReference) SetIGH() error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetIGH(sr.cval).Err()
 }
 
@@ -774,6 +1226,11 @@ func (sr SpatialReference) SetIGH() error {
 func (sr SpatialReference) SetGEOS(
 	centralMeridian, satelliteHeight, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetGEOS(
 		sr.cval,
 		C.double(centralMeridian),
@@ -787,6 +1244,11 @@ func (sr SpatialReference) SetGEOS(
 func (sr SpatialReference) SetGSTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetGaussSchreiberTMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -801,6 +1263,11 @@ func (sr SpatialReference) SetGSTM(
 func (sr SpatialReference) SetGnomonic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetGnomonic(
 		sr.cval,
 		C.double(centerLat),
@@ -814,6 +1281,11 @@ func (sr SpatialReference) SetGnomonic(
 func (sr SpatialReference) SetHOM(
 	centerLat, centerLong, azimuth, rectToSkew, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetHOM(
 		sr.cval,
 		C.double(centerLat),
@@ -830,6 +1302,11 @@ func (sr SpatialReference) SetHOM(
 func (sr SpatialReference) SetHOM2PNO(
 	centerLat, lat1, long1, lat2, long2, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetHOM2PNO(
 		sr.cval,
 		C.double(centerLat),
@@ -847,6 +1324,11 @@ func (sr SpatialReference) SetHOM2PNO(
 func (sr SpatialReference) SetIWMPolyconic(
 	lat1, lat2, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetIWMPolyconic(
 		sr.cval,
 		C.double(lat1),
@@ -861,6 +1343,11 @@ func (sr SpatialReference) SetIWMPolyconic(
 func (sr SpatialReference) SetKrovak(
 	centerLat, centerLong, azimuth, psuedoStdParallel, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetKrovak(
 		sr.cval,
 		C.double(centerLat),
@@ -877,6 +1364,11 @@ func (sr SpatialReference) SetKrovak(
 func (sr SpatialReference) SetLAEA(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetLAEA(
 		sr.cval,
 		C.double(centerLat),
@@ -890,6 +1382,11 @@ func (sr SpatialReference) SetLAEA(
 func (sr SpatialReference) SetLCC(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetLCC(
 		sr.cval,
 		C.double(stdp1),
@@ -905,6 +1402,11 @@ func (sr SpatialReference) SetLCC(
 func (sr SpatialReference) SetLCC1SP(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetLCC1SP(
 		sr.cval,
 		C.double(centerLat),
@@ -919,6 +1421,11 @@ func (sr SpatialReference) SetLCC1SP(
 func (sr SpatialReference) SetLCCB(
 	stdp1, stdp2, centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetLCCB(
 		sr.cval,
 		C.double(stdp1),
@@ -934,6 +1441,11 @@ func (sr SpatialReference) SetLCCB(
 func (sr SpatialReference) SetMC(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetMC(
 		sr.cval,
 		C.double(centerLat),
@@ -947,6 +1459,11 @@ func (sr SpatialReference) SetMC(
 func (sr SpatialReference) SetMercator(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetMercator(
 		sr.cval,
 		C.double(centerLat),
@@ -961,6 +1478,11 @@ func (sr SpatialReference) SetMercator(
 func (sr SpatialReference) SetMollweide(
 	centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetMollweide(
 		sr.cval,
 		C.double(centralMeridian),
@@ -973,6 +1495,11 @@ func (sr SpatialReference) SetMollweide(
 func (sr SpatialReference) SetNZMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetNZMG(
 		sr.cval,
 		C.double(centerLat),
@@ -986,6 +1513,11 @@ func (sr SpatialReference) SetNZMG(
 func (sr SpatialReference) SetOS(
 	originLat, meridian, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetOS(
 		sr.cval,
 		C.double(originLat),
@@ -1000,6 +1532,11 @@ func (sr SpatialReference) SetOS(
 func (sr SpatialReference) SetOrthographic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetOrthographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1013,6 +1550,11 @@ func (sr SpatialReference) SetOrthographic(
 func (sr SpatialReference) SetPolyconic(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetPolyconic(
 		sr.cval,
 		C.double(centerLat),
@@ -1026,6 +1568,11 @@ func (sr SpatialReference) SetPolyconic(
 func (sr SpatialReference) SetPS(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetPS(
 		sr.cval,
 		C.double(centerLat),
@@ -1040,6 +1587,11 @@ func (sr SpatialReference) SetPS(
 func (sr SpatialReference) SetRobinson(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetRobinson(
 		sr.cval,
 		C.double(centerLong),
@@ -1052,6 +1604,11 @@ func (sr SpatialReference) SetRobinson(
 func (sr SpatialReference) SetSinusoidal(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetSinusoidal(
 		sr.cval,
 		C.double(centerLong),
@@ -1064,6 +1621,11 @@ func (sr SpatialReference) SetSinusoidal(
 func (sr SpatialReference) SetStereographic(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetStereographic(
 		sr.cval,
 		C.double(centerLat),
@@ -1078,6 +1640,11 @@ func (sr SpatialReference) SetStereographic(
 func (sr SpatialReference) SetSOC(
 	latitudeOfOrigin, centralMeridian, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetSOC(
 		sr.cval,
 		C.double(latitudeOfOrigin),
@@ -1091,6 +1658,11 @@ func (sr SpatialReference) SetSOC(
 func (sr SpatialReference) SetTM(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetTM(
 		sr.cval,
 		C.double(centerLat),
@@ -1105,6 +1677,11 @@ func (sr SpatialReference) SetTM(
 func (sr SpatialReference) SetTMVariant(
 	variantName string, centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(variantName)
 	defer C.free(unsafe.Pointer(cName))
 	return C.OSRSetTMVariant(
@@ -1122,6 +1699,11 @@ func (sr SpatialReference) SetTMVariant(
 func (sr SpatialReference) SetTMG(
 	centerLat, centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetTMG(
 		sr.cval,
 		C.double(centerLat),
@@ -1135,6 +1717,11 @@ func (sr SpatialReference) SetTMG(
 func (sr SpatialReference) SetTMSO(
 	centerLat, centerLong, scale, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetTMSO(
 		sr.cval,
 		C.double(centerLat),
@@ -1149,6 +1736,11 @@ func (sr SpatialReference) SetTMSO(
 func (sr SpatialReference) SetVDG(
 	centerLong, falseEasting, falseNorthing float64,
 ) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(sr)
+	if sr.closed() {
+		return ErrClosed
+	}
 	return C.OSRSetVDG(
 		sr.cval,
 		C.double(centerLong),
@@ -1168,6 +1760,7 @@ func CleanupSR() {
 
 type CoordinateTransform struct {
 	cval C.OGRCoordinateTransformationH
+	life *lifetime
 }
 
 // Create a new CoordinateTransform
@@ -1175,16 +1768,45 @@ func CreateCoordinateTransform(
 	source SpatialReference,
 	dest SpatialReference,
 ) CoordinateTransform {
+	defer runtime.KeepAlive(source)
+	defer runtime.KeepAlive(dest)
 	ct := C.OCTNewCoordinateTransformation(source.cval, dest.cval)
-	return CoordinateTransform{ct}
+	if ct == nil {
+		return CoordinateTransform{}
+	}
+	return CoordinateTransform{
+		cval: ct,
+		life: newLifetime(func() { C.OCTDestroyCoordinateTransformation(ct) }),
+	}
 }
 
 // Destroy CoordinateTransform
 func (ct CoordinateTransform) Destroy() {
-	C.OCTDestroyCoordinateTransformation(ct.cval)
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return
+	}
+	if ct.life.release() {
+		C.OCTDestroyCoordinateTransformation(ct.cval)
+	}
+}
+
+// Close destroys the coordinate transform if it is owned by the caller.
+// Closing a coordinate transform that is already closed does nothing.
+func (ct *CoordinateTransform) Close() error {
+	defer runtime.KeepAlive(ct)
+	if ct.cval != nil && ct.life.owned() && ct.life.release() {
+		C.OCTDestroyCoordinateTransformation(ct.cval)
+	}
+	ct.cval = nil
+	return nil
 }
 
 func (ct CoordinateTransform) Transform(numPoints int, xPoints []float64, yPoints []float64, zPoints []float64) bool {
+	defer runtime.KeepAlive(ct)
+	if ct.closed() {
+		return false
+	}
 	val := C.OCTTransform(ct.cval, C.int(numPoints), (*C.double)(unsafe.Pointer(&xPoints[0])), (*C.double)(unsafe.Pointer(&yPoints[0])), (*C.double)(unsafe.Pointer(&zPoints[0])))
 	return int(val) != 0
 }
@@ -10,7 +10,10 @@ package gdal
 #cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
 */
 import "C"
-import "unsafe"
+import (
+	"runtime"
+	"unsafe"
+)
 
 type RATFieldType int
 
@@ -47,70 +50,130 @@ const (
 // Construct empty raster attribute table
 func CreateRasterAttributeTable() RasterAttributeTable {
 	rat := C.GDALCreateRasterAttributeTable()
-	return RasterAttributeTable{rat}
+	return RasterAttributeTable{
+		cval: rat,
+		life: newLifetime(func() { C.GDALDestroyRasterAttributeTable(rat) }),
+	}
 }
 
 // Destroy a RAT
 func (rat RasterAttributeTable) Destroy() {
-	C.GDALDestroyRasterAttributeTable(rat.cval)
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return
+	}
+	if rat.life.release() {
+		C.GDALDestroyRasterAttributeTable(rat.cval)
+	}
+}
+
+// Close destroys the RAT if it is owned by the caller.  Closing a RAT that is
+// already closed does nothing.
+func (rat *RasterAttributeTable) Close() error {
+	defer runtime.KeepAlive(rat)
+	if rat.cval != nil && rat.life.owned() && rat.life.release() {
+		C.GDALDestroyRasterAttributeTable(rat.cval)
+	}
+	rat.cval = nil
+	return nil
 }
 
 // Fetch table column count
 func (rat RasterAttributeTable) ColumnCount() int {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	count := C.GDALRATGetColumnCount(rat.cval)
 	return int(count)
 }
 
 // Fetch the name of indicated column
 func (rat RasterAttributeTable) NameOfCol(index int) string {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return ""
+	}
 	name := C.GDALRATGetNameOfCol(rat.cval, C.int(index))
 	return C.GoString(name)
 }
 
 // Fetch the usage of indicated column
 func (rat RasterAttributeTable) UsageOfCol(index int) RATFieldUsage {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	rfu := C.GDALRATGetUsageOfCol(rat.cval, C.int(index))
 	return RATFieldUsage(rfu)
 }
 
 // Fetch the type of indicated column
 func (rat RasterAttributeTable) TypeOfCol(index int) RATFieldType {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	rft := C.GDALRATGetTypeOfCol(rat.cval, C.int(index))
 	return RATFieldType(rft)
 }
 
 // Fetch column index for indicated usage
 func (rat RasterAttributeTable) ColOfUsage(rfu RATFieldUsage) int {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	index := C.GDALRATGetColOfUsage(rat.cval, C.GDALRATFieldUsage(rfu))
 	return int(index)
 }
 
 // Fetch row count
 func (rat RasterAttributeTable) RowCount() int {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	count := C.GDALRATGetRowCount(rat.cval)
 	return int(count)
 }
 
 // Fetch field value as string
 func (rat RasterAttributeTable) ValueAsString(row, field int) string {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return ""
+	}
 	cString := C.GDALRATGetValueAsString(rat.cval, C.int(row), C.int(field))
 	return C.GoString(cString)
 }
 
 // Fetch field value as integer
 func (rat RasterAttributeTable) ValueAsInt(row, field int) int {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	val := C.GDALRATGetValueAsInt(rat.cval, C.int(row), C.int(field))
 	return int(val)
 }
 
 // Fetch field value as float64
 func (rat RasterAttributeTable) ValueAsFloat64(row, field int) float64 {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0
+	}
 	val := C.GDALRATGetValueAsDouble(rat.cval, C.int(row), C.int(field))
 	return float64(val)
 }
 
 // Set field value from string
 func (rat RasterAttributeTable) SetValueAsString(row, field int, val string) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return
+	}
 	cVal := C.CString(val)
 	defer C.free(unsafe.Pointer(cVal))
 	C.GDALRATSetValueAsString(rat.cval, C.int(row), C.int(field), cVal)
@@ -118,21 +181,38 @@ func (rat RasterAttributeTable) SetValueAsString(row, field int, val string) {
 
 // Set field value from integer
 func (rat RasterAttributeTable) SetValueAsInt(row, field, val int) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return
+	}
 	C.GDALRATSetValueAsInt(rat.cval, C.int(row), C.int(field), C.int(val))
 }
 
 // Set field value from float64
 func (rat RasterAttributeTable) SetValueAsFloat64(row, field int, val float64) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return
+	}
 	C.GDALRATSetValueAsDouble(rat.cval, C.int(row), C.int(field), C.double(val))
 }
 
 // Set row count
 func (rat RasterAttributeTable) SetRowCount(count int) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return
+	}
 	C.GDALRATSetRowCount(rat.cval, C.int(count))
 }
 
 // Create new column
 func (rat RasterAttributeTable) CreateColumn(name string, rft RATFieldType, rfu RATFieldUsage) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return ErrClosed
+	}
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
 	return C.GDALRATCreateColumn(rat.cval, cName, C.GDALRATFieldType(rft), C.GDALRATFieldUsage(rfu)).Err()
@@ -140,24 +220,43 @@ func (rat RasterAttributeTable) CreateColumn(name string, rft RATFieldType, rfu
 
 // Set linear binning information
 func (rat RasterAttributeTable) SetLinearBinning(row0min, binsize float64) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return ErrClosed
+	}
 	return C.GDALRATSetLinearBinning(rat.cval, C.double(row0min), C.double(binsize)).Err()
 }
 
 // Fetch linear binning information
 func (rat RasterAttributeTable) LinearBinning() (row0min, binsize float64, exists bool) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0, 0, false
+	}
 	success := C.GDALRATGetLinearBinning(rat.cval, (*C.double)(&row0min), (*C.double)(&binsize))
 	return row0min, binsize, success != 0
 }
 
 // Initialize RAT from color table
 func (rat RasterAttributeTable) FromColorTable(ct ColorTable) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(rat)
+	defer runtime.KeepAlive(ct)
+	if rat.closed() {
+		return ErrClosed
+	}
 	return C.GDALRATInitializeFromColorTable(rat.cval, ct.cval).Err()
 }
 
 // Translate RAT to a color table
 func (rat RasterAttributeTable) ToColorTable(count int) ColorTable {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return ColorTable{}
+	}
 	ct := C.GDALRATTranslateToColorTable(rat.cval, C.int(count))
-	return ColorTable{ct}
+	return newColorTable(ct)
 }
 
 // Dump RAT in readable form to a file
@@ -165,6 +264,10 @@ func (rat RasterAttributeTable) ToColorTable(count int) ColorTable {
 
 // Get row for pixel value
 func (rat RasterAttributeTable) RowOfValue(val float64) (int, bool) {
+	defer runtime.KeepAlive(rat)
+	if rat.closed() {
+		return 0, false
+	}
 	row := C.GDALRATGetRowOfValue(rat.cval, C.double(val))
 	return int(row), row != -1
 }
@@ -0,0 +1,319 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// Transformer converts coordinates between the pixel/line space of a source
+// raster and a destination space, georeferenced or the pixel/line space of a
+// destination raster, depending on the kind of transformer.
+type Transformer interface {
+	// Transform converts the points in place, from source to destination
+	// space, or the reverse if dstToSrc is set.  z may be nil.  The result
+	// reports which points were transformed, an error being returned along
+	// with it when some could not be.
+	Transform(dstToSrc bool, x, y, z []float64) ([]bool, error)
+	// Destroy frees the transformer
+	Destroy()
+	// Close frees the transformer, like Destroy
+	Close() error
+	// arg returns the C transformer argument
+	arg() unsafe.Pointer
+}
+
+// ErrTransformerDestroyed is returned when using a destroyed Transformer.  It
+// wraps ErrClosed, like the errors of the other closed handles.
+var ErrTransformerDestroyed = fmt.Errorf("transformer destroyed: %w", ErrClosed)
+
+// transformer is a transformer created by GDAL, used through
+// GDALUseTransformer which dispatches to the matching transform function.
+type transformer struct {
+	cval unsafe.Pointer
+}
+
+func newTransformer(cval unsafe.Pointer) (Transformer, error) {
+	if cval == nil {
+		return nil, lastError(CE_Failure)
+	}
+	return &transformer{cval}, nil
+}
+
+func (t *transformer) arg() unsafe.Pointer {
+	return t.cval
+}
+
+func (t *transformer) Transform(dstToSrc bool, x, y, z []float64) ([]bool, error) {
+	defer errorScope()()
+	if t.cval == nil {
+		return nil, ErrTransformerDestroyed
+	}
+	n := len(x)
+	if len(y) != n || (z != nil && len(z) != n) {
+		return nil, fmt.Errorf("x, y and z hold %d, %d and %d points", len(x), len(y), len(z))
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if z == nil {
+		z = make([]float64, n)
+	}
+	success := make([]C.int, n)
+	ok := C.GDALUseTransformer(
+		t.cval,
+		BoolToCInt(dstToSrc),
+		C.int(n),
+		(*C.double)(unsafe.Pointer(&x[0])),
+		(*C.double)(unsafe.Pointer(&y[0])),
+		(*C.double)(unsafe.Pointer(&z[0])),
+		&success[0],
+	)
+	result := make([]bool, n)
+	for i := range success {
+		result[i] = success[i] != 0
+	}
+	if ok == 0 {
+		return result, lastError(CE_Failure)
+	}
+	return result, nil
+}
+
+func (t *transformer) Destroy() {
+	if t.cval == nil {
+		return
+	}
+	C.GDALDestroyTransformer(t.cval)
+	t.cval = nil
+}
+
+func (t *transformer) Close() error {
+	t.Destroy()
+	return nil
+}
+
+// transformerFunc is the C transformer function matching any transformer
+// argument created by GDAL.
+func transformerFunc() C.GDALTransformerFunc {
+	return C.GDALTransformerFunc(C.GDALUseTransformer)
+}
+
+// Create a transformer from the pixel/line space of src to the pixel/line
+// space of dst, through their georeferencing.  dst may be nil, the transformer
+// then converting to the georeferenced coordinates of src, or of the
+// DST_SRS option.  The options are those of GDALCreateGenImgProjTransformer2,
+// for example SRC_METHOD=GCP_TPS, DST_SRS=EPSG:4326 or MAX_GCP_ORDER=2.
+func CreateGenImgProjTransformer2(src, dst *Dataset, options []string) (Transformer, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	defer runtime.KeepAlive(dst)
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	var srcH, dstH C.GDALDatasetH
+	if src != nil {
+		srcH = src.cval
+	}
+	if dst != nil {
+		dstH = dst.cval
+	}
+	return newTransformer(C.GDALCreateGenImgProjTransformer2(
+		srcH,
+		dstH,
+		(**C.char)(unsafe.Pointer(&opts[0])),
+	))
+}
+
+// Create a polynomial transformer from the pixel/line space of the GCPs to
+// their georeferenced space, of the given order (1 to 3, or 0 to pick one
+// from the number of GCPs).  reversed swaps the two spaces.
+func CreateGCPTransformer(gcps []GCP, order int, reversed bool) (Transformer, error) {
+	defer errorScope()()
+	if len(gcps) == 0 {
+		return nil, errors.New("no GCP given")
+	}
+	cList, free := cGCPs(gcps)
+	defer free()
+	return newTransformer(C.GDALCreateGCPTransformer(
+		C.int(len(cList)),
+		&cList[0],
+		C.int(order),
+		BoolToCInt(reversed),
+	))
+}
+
+// Create a thin plate spline transformer from the pixel/line space of the GCPs
+// to their georeferenced space.  reversed swaps the two spaces.
+func CreateTPSTransformer(gcps []GCP, reversed bool) (Transformer, error) {
+	defer errorScope()()
+	if len(gcps) == 0 {
+		return nil, errors.New("no GCP given")
+	}
+	cList, free := cGCPs(gcps)
+	defer free()
+	return newTransformer(C.GDALCreateTPSTransformer(
+		C.int(len(cList)),
+		&cList[0],
+		BoolToCInt(reversed),
+	))
+}
+
+// Create a transformer from the pixel/line space of a scene to longitude,
+// latitude and height, from its rational polynomial coefficients as found in
+// the "RPC" metadata domain.  pixErrThreshold is the error in pixels below
+// which the inverse transformation stops iterating.  The options are those of
+// GDALCreateRPCTransformerV2, for example RPC_HEIGHT=100 or RPC_DEM=dem.tif.
+func CreateRPCTransformer(
+	rpcMetadata []string,
+	reversed bool,
+	pixErrThreshold float64,
+	options []string,
+) (Transformer, error) {
+	defer errorScope()()
+	length := len(rpcMetadata)
+	md := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		md[i] = C.CString(rpcMetadata[i])
+		defer C.free(unsafe.Pointer(md[i]))
+	}
+	md[length] = (*C.char)(unsafe.Pointer(nil))
+
+	var info C.GDALRPCInfoV2
+	if C.GDALExtractRPCInfoV2((**C.char)(unsafe.Pointer(&md[0])), &info) == 0 {
+		return nil, errors.New("incomplete RPC metadata")
+	}
+
+	length = len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	return newTransformer(C.GDALCreateRPCTransformerV2(
+		&info,
+		BoolToCInt(reversed),
+		C.double(pixErrThreshold),
+		(**C.char)(unsafe.Pointer(&opts[0])),
+	))
+}
+
+// Create a transformer from the pixel/line space of base to the georeferenced
+// space described by geolocation arrays, as found in the "GEOLOCATION"
+// metadata domain.  reversed swaps the two spaces.
+func CreateGeoLocTransformer(base *Dataset, geolocMetadata []string, reversed bool) (Transformer, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(base)
+	if base.closed() {
+		return nil, ErrClosed
+	}
+	length := len(geolocMetadata)
+	md := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		md[i] = C.CString(geolocMetadata[i])
+		defer C.free(unsafe.Pointer(md[i]))
+	}
+	md[length] = (*C.char)(unsafe.Pointer(nil))
+
+	return newTransformer(C.GDALCreateGeoLocTransformer(
+		base.cval,
+		(**C.char)(unsafe.Pointer(&md[0])),
+		BoolToCInt(reversed),
+	))
+}
+
+// Create a transformer approximating base by linear interpolation along
+// scanlines, with an error of at most maxError pixels.  The approximating
+// transformer takes ownership of base, which must not be used or destroyed
+// afterwards.
+func CreateApproxTransformer(base Transformer, maxError float64) (Transformer, error) {
+	defer errorScope()()
+	baseArg := base.arg()
+	if baseArg == nil {
+		return nil, ErrTransformerDestroyed
+	}
+	approx := C.GDALCreateApproxTransformer(transformerFunc(), baseArg, C.double(maxError))
+	if approx == nil {
+		return nil, lastError(CE_Failure)
+	}
+	C.GDALApproxTransformerOwnsSubtransformer(approx, C.TRUE)
+	if t, ok := base.(*transformer); ok {
+		t.cval = nil
+	}
+	return &transformer{approx}, nil
+}
+
+// Suggest the geotransform and size of an output raster holding all of src,
+// transformed to georeferenced coordinates by t.
+func SuggestedWarpOutput(src *Dataset, t Transformer) (geoTransform [6]float64, pixels, lines int, err error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return geoTransform, 0, 0, ErrClosed
+	}
+	if t.arg() == nil {
+		return geoTransform, 0, 0, ErrTransformerDestroyed
+	}
+	var cPixels, cLines C.int
+	err = C.GDALSuggestedWarpOutput(
+		src.cval,
+		transformerFunc(),
+		t.arg(),
+		(*C.double)(unsafe.Pointer(&geoTransform[0])),
+		&cPixels,
+		&cLines,
+	).Err()
+	return geoTransform, int(cPixels), int(cLines), err
+}
+
+// Serialize the transformer to an XML document
+func SerializeTransformer(t Transformer) (string, error) {
+	defer errorScope()()
+	if t.arg() == nil {
+		return "", ErrTransformerDestroyed
+	}
+	node := C.GDALSerializeTransformer(transformerFunc(), t.arg())
+	if node == nil {
+		return "", lastError(CE_Failure)
+	}
+	defer C.CPLDestroyXMLNode(node)
+	xml := C.CPLSerializeXMLTree(node)
+	defer C.VSIFree(unsafe.Pointer(xml))
+	return C.GoString(xml), nil
+}
+
+// Create a transformer from an XML document produced by SerializeTransformer
+func DeserializeTransformer(xml string) (Transformer, error) {
+	defer errorScope()()
+	cXML := C.CString(xml)
+	defer C.free(unsafe.Pointer(cXML))
+	node := C.CPLParseXMLString(cXML)
+	if node == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.CPLDestroyXMLNode(node)
+
+	var fn C.GDALTransformerFunc
+	var arg unsafe.Pointer
+	if err := C.GDALDeserializeTransformer(node, &fn, &arg).Err(); err != nil {
+		return nil, err
+	}
+	return newTransformer(arg)
+}
@@ -0,0 +1,354 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+/* ==================================================================== */
+/*      GDAL utilities (gdal_utils.h)                                   */
+/* ==================================================================== */
+
+// The utilities take their options as the command line switches of the
+// matching program, such as []string{"-of", "COG", "-co", "COMPRESS=LZW"}.
+
+// cStringList converts a list of strings into a NULL terminated C string list.
+// The returned function frees the C strings.
+func cStringList(list []string) ([]*C.char, func()) {
+	length := len(list)
+	cList := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		cList[i] = C.CString(list[i])
+	}
+	cList[length] = (*C.char)(unsafe.Pointer(nil))
+	return cList, func() {
+		for i := 0; i < length; i++ {
+			C.free(unsafe.Pointer(cList[i]))
+		}
+	}
+}
+
+// optionalCString returns a C string for s, or nil if s is empty.
+func optionalCString(s string) (*C.char, func()) {
+	if s == "" {
+		return nil, func() {}
+	}
+	cs := C.CString(s)
+	return cs, func() { C.free(unsafe.Pointer(cs)) }
+}
+
+// utilityError returns the error of a failed utility call.
+func utilityError(usageError C.int) error {
+	err := lastError(CE_Failure)
+	if usageError != 0 && err.Msg == "" {
+		err.Msg = "invalid utility options"
+	}
+	return err
+}
+
+var errNoSource = errors.New("no source dataset")
+
+// Translate converts a raster dataset, written to dst.  This is the
+// equivalent of the gdal_translate utility.
+func Translate(
+	dst string,
+	src *Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return nil, ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALTranslateOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALTranslateOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALTranslateOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+
+	var usageError C.int
+	h := C.GDALTranslate(cDst, src.cval, opts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
+
+// VectorTranslate converts a vector dataset, written to dst.  This is the
+// equivalent of the ogr2ogr utility.
+func VectorTranslate(
+	dst string,
+	src *Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return nil, ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALVectorTranslateOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALVectorTranslateOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALVectorTranslateOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+
+	cSrc := []C.GDALDatasetH{src.cval}
+	var usageError C.int
+	h := C.GDALVectorTranslate(cDst, nil, 1, &cSrc[0], opts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
+
+// Info returns a description of a raster dataset.  This is the equivalent of
+// the gdalinfo utility, pass "-json" for a JSON document.
+func Info(ds *Dataset, options []string) (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return "", ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALInfoOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return "", lastError(CE_Failure)
+	}
+	defer C.GDALInfoOptionsFree(opts)
+
+	info := C.GDALInfo(ds.cval, opts)
+	if info == nil {
+		return "", lastError(CE_Failure)
+	}
+	defer C.VSIFree(unsafe.Pointer(info))
+	return C.GoString(info), nil
+}
+
+// VectorInfo returns a description of a vector dataset.  This is the
+// equivalent of the ogrinfo utility, pass "-json" for a JSON document.
+// Requires GDAL 3.7.
+func VectorInfo(ds *Dataset, options []string) (string, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(ds)
+	if ds.closed() {
+		return "", ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	info := C.goGDALVectorInfo(ds.cval, (**C.char)(unsafe.Pointer(&cOpts[0])))
+	if info == nil {
+		return "", lastError(CE_Failure)
+	}
+	defer C.VSIFree(unsafe.Pointer(info))
+	return C.GoString(info), nil
+}
+
+// BuildVRT builds a VRT mosaic of the sources, written to dst.  The sources
+// are given either as open datasets, or as file names, the other being nil.
+// This is the equivalent of the gdalbuildvrt utility.
+func BuildVRT(
+	dst string,
+	srcs []*Dataset,
+	srcNames []string,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(srcs)
+	if len(srcs) == 0 && len(srcNames) == 0 {
+		return nil, errNoSource
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALBuildVRTOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALBuildVRTOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALBuildVRTOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+
+	var h C.GDALDatasetH
+	var usageError C.int
+	if len(srcs) > 0 {
+		cSrcs := make([]C.GDALDatasetH, len(srcs))
+		for i, src := range srcs {
+			if src.closed() {
+				return nil, ErrClosed
+			}
+			cSrcs[i] = src.cval
+		}
+		h = C.GDALBuildVRT(cDst, C.int(len(cSrcs)), &cSrcs[0], nil, opts, &usageError)
+	} else {
+		cNames, freeNames := cStringList(srcNames)
+		defer freeNames()
+		h = C.GDALBuildVRT(
+			cDst, C.int(len(srcNames)), nil,
+			(**C.char)(unsafe.Pointer(&cNames[0])),
+			opts, &usageError,
+		)
+	}
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
+
+// DEMProcessing computes a product from a DEM, written to dst.  processing is
+// one of "hillshade", "slope", "aspect", "color-relief", "TRI", "TPI" or
+// "roughness", and colorFilename the color configuration file used by
+// "color-relief".  This is the equivalent of the gdaldem utility.
+func DEMProcessing(
+	dst string,
+	src *Dataset,
+	processing string,
+	colorFilename string,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return nil, ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALDEMProcessingOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALDEMProcessingOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALDEMProcessingOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+	cProcessing := C.CString(processing)
+	defer C.free(unsafe.Pointer(cProcessing))
+	cColorFilename, freeColor := optionalCString(colorFilename)
+	defer freeColor()
+
+	var usageError C.int
+	h := C.GDALDEMProcessing(cDst, src.cval, cProcessing, cColorFilename, opts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
+
+// Nearblack converts nearly black or white borders to exact values, written
+// to dst.  This is the equivalent of the nearblack utility.
+func Nearblack(
+	dst string,
+	src *Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return nil, ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALNearblackOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALNearblackOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALNearblackOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+
+	var usageError C.int
+	h := C.GDALNearblack(cDst, nil, src.cval, opts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
+
+// Rasterize burns the vector geometries of src into a new raster, written to
+// dst.  This is the equivalent of the gdal_rasterize utility.
+func Rasterize(
+	dst string,
+	src *Dataset,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) (*Dataset, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(src)
+	if src.closed() {
+		return nil, ErrClosed
+	}
+	cOpts, free := cStringList(options)
+	defer free()
+	opts := C.GDALRasterizeOptionsNew((**C.char)(unsafe.Pointer(&cOpts[0])), nil)
+	if opts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALRasterizeOptionsFree(opts)
+
+	cProgress, cArg, release := progressProxy(progress, data)
+	defer release()
+	C.GDALRasterizeOptionsSetProgress(opts, cProgress, cArg)
+
+	cDst := C.CString(dst)
+	defer C.free(unsafe.Pointer(cDst))
+
+	var usageError C.int
+	h := C.GDALRasterize(cDst, nil, src.cval, opts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return newDataset(h), nil
+}
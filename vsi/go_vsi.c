@@ -0,0 +1,17 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "go_vsi.h"
+
+int goVSIStat(const char *name, GIntBig *size, GIntBig *mtime, int *perm, int *isDir) {
+	VSIStatBufL buf;
+	if (VSIStatL(name, &buf) != 0) {
+		return -1;
+	}
+	*size = (GIntBig)buf.st_size;
+	*mtime = (GIntBig)buf.st_mtime;
+	*perm = (int)(buf.st_mode & 0777);
+	*isDir = VSI_ISDIR(buf.st_mode) ? 1 : 0;
+	return 0;
+}
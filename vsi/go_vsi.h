@@ -0,0 +1,17 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#ifndef GO_VSI_H_
+#define GO_VSI_H_
+
+#include <stdlib.h>
+
+#include <cpl_error.h>
+#include <cpl_vsi.h>
+
+// stat a file, returning 0 on success along with its size, modification time,
+// permission bits and whether it is a directory
+int goVSIStat(const char *name, GIntBig *size, GIntBig *mtime, int *perm, int *isDir);
+
+#endif // GO_VSI_H_
@@ -0,0 +1,425 @@
+// Package vsi gives access to the GDAL virtual file systems, such as /vsimem/,
+// /vsizip/, /vsitar/ or /vsigzip/, through the io/fs interfaces.  The files
+// listed this way can be handed to gdal.Open under their full VSI name.
+package vsi
+
+/*
+#include "go_vsi.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+var (
+	_ fs.ReadDirFS   = (*vsiFS)(nil)
+	_ fs.StatFS      = (*vsiFS)(nil)
+	_ fs.ReadDirFile = (*dir)(nil)
+	_ fs.File        = (*file)(nil)
+	_ io.Seeker      = (*file)(nil)
+)
+
+// vsiFS is the file system rooted at a VSI path
+type vsiFS struct {
+	prefix string
+}
+
+// Return the file system rooted at a VSI path, such as
+// "/vsizip//data/scenes.zip" or "/vsimem/uploads".  The names given to the
+// file system are slash separated paths relative to the prefix.
+func FS(prefix string) fs.FS {
+	return &vsiFS{prefix}
+}
+
+// path returns the VSI path of a file system name, or an error if the name is
+// not valid
+func (fsys *vsiFS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return fsys.prefix, nil
+	}
+	return strings.TrimSuffix(fsys.prefix, "/") + "/" + name, nil
+}
+
+// Open a file for reading.  Files implement io.Seeker, and directories
+// fs.ReadDirFile.
+func (fsys *vsiFS) Open(name string) (fs.File, error) {
+	defer errorScope()()
+	p, err := fsys.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := stat("open", p, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &dir{fsys: fsys, name: name, info: info}, nil
+	}
+
+	cPath := C.CString(p)
+	defer C.free(unsafe.Pointer(cPath))
+	cMode := C.CString("rb")
+	defer C.free(unsafe.Pointer(cMode))
+	C.CPLErrorReset()
+	fp := C.VSIFOpenL(cPath, cMode)
+	if fp == nil {
+		return nil, lastError("open", name)
+	}
+	return &file{name: name, path: p, fp: fp}, nil
+}
+
+// Stat a file
+func (fsys *vsiFS) Stat(name string) (fs.FileInfo, error) {
+	p, err := fsys.path("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return stat("stat", p, name)
+}
+
+// Read a directory, returning its entries sorted by name
+func (fsys *vsiFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := fsys.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := stat("readdir", p, name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	cPath := C.CString(p)
+	defer C.free(unsafe.Pointer(cPath))
+	list := C.VSIReadDir(cPath)
+	if list == nil {
+		return nil, nil
+	}
+	defer C.CSLDestroy(list)
+
+	var entries []fs.DirEntry
+	for _, cEntry := range unsafe.Slice(list, C.CSLCount(list)) {
+		entry := C.GoString(cEntry)
+		if entry == "." || entry == ".." {
+			continue
+		}
+		info, err := stat("readdir", strings.TrimSuffix(p, "/")+"/"+entry, path.Join(name, entry))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+/* -------------------------------------------------------------------- */
+/*      Files                                                           */
+/* -------------------------------------------------------------------- */
+
+// file is a regular file opened for reading
+type file struct {
+	name string
+	path string
+	fp   *C.VSILFILE
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	if f.fp == nil {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	return stat("stat", f.path, f.name)
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	defer errorScope()()
+	if f.fp == nil {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := int(C.VSIFReadL(unsafe.Pointer(&p[0]), 1, C.size_t(len(p)), f.fp))
+	if n < len(p) {
+		if C.VSIFEofL(f.fp) != 0 {
+			return n, io.EOF
+		}
+		return n, lastError("read", f.name)
+	}
+	return n, nil
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	defer errorScope()()
+	if f.fp == nil {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(C.VSIFTellL(f.fp))
+	case io.SeekEnd:
+		if C.VSIFSeekL(f.fp, 0, C.SEEK_END) != 0 {
+			return 0, lastError("seek", f.name)
+		}
+		offset += int64(C.VSIFTellL(f.fp))
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: errors.New("negative position")}
+	}
+	if C.VSIFSeekL(f.fp, C.vsi_l_offset(offset), C.SEEK_SET) != 0 {
+		return 0, lastError("seek", f.name)
+	}
+	return offset, nil
+}
+
+func (f *file) Close() error {
+	defer errorScope()()
+	if f.fp == nil {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	ret := C.VSIFCloseL(f.fp)
+	f.fp = nil
+	if ret != 0 {
+		return lastError("close", f.name)
+	}
+	return nil
+}
+
+// dir is a directory opened for reading its entries
+type dir struct {
+	fsys    *vsiFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	read    bool
+	closed  bool
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "stat", Path: d.name, Err: fs.ErrClosed}
+	}
+	return d.info, nil
+}
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+func (d *dir) Close() error {
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: d.name, Err: fs.ErrClosed}
+	}
+	d.closed = true
+	return nil
+}
+
+// fileInfo describes a file returned by stat
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (info *fileInfo) Name() string       { return info.name }
+func (info *fileInfo) Size() int64        { return info.size }
+func (info *fileInfo) Mode() fs.FileMode  { return info.mode }
+func (info *fileInfo) ModTime() time.Time { return info.modTime }
+func (info *fileInfo) IsDir() bool        { return info.mode.IsDir() }
+func (info *fileInfo) Sys() any           { return nil }
+
+// stat describes the file at a VSI path, reporting errors of op against name
+func stat(op, p, name string) (fs.FileInfo, error) {
+	cPath := C.CString(p)
+	defer C.free(unsafe.Pointer(cPath))
+	var size, mtime C.GIntBig
+	var perm, isDir C.int
+	if C.goVSIStat(cPath, &size, &mtime, &perm, &isDir) != 0 {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	mode := fs.FileMode(perm)
+	if isDir != 0 {
+		mode |= fs.ModeDir
+	}
+	return &fileInfo{
+		name:    path.Base(name),
+		size:    int64(size),
+		mode:    mode,
+		modTime: time.Unix(int64(mtime), 0),
+	}, nil
+}
+
+// errorScope locks the calling goroutine to its OS thread and resets the GDAL
+// error state of the thread, so that lastError() reports the errors of the
+// calls made until the returned function is called, and not stale ones
+func errorScope() func() {
+	runtime.LockOSThread()
+	C.CPLErrorReset()
+	return runtime.UnlockOSThread
+}
+
+// lastError returns the last GDAL error as the error of an operation on name
+func lastError(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: lastErrorMsg()}
+}
+
+// lastErrorMsg returns the message of the last GDAL error
+func lastErrorMsg() error {
+	msg := C.GoString(C.CPLGetLastErrorMsg())
+	if msg == "" {
+		msg = "I/O error"
+	}
+	return errors.New(msg)
+}
+
+/* -------------------------------------------------------------------- */
+/*      Writing                                                         */
+/* -------------------------------------------------------------------- */
+
+// writer is a file opened for writing
+type writer struct {
+	name string
+	fp   *C.VSILFILE
+}
+
+// Create or truncate the file at a VSI path, such as "/vsimem/out.tif" or
+// "/vsizip//vsimem/out.zip/out.tif", and open it for writing
+func Create(name string) (io.WriteCloser, error) {
+	defer errorScope()()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cMode := C.CString("wb")
+	defer C.free(unsafe.Pointer(cMode))
+	C.CPLErrorReset()
+	fp := C.VSIFOpenL(cName, cMode)
+	if fp == nil {
+		return nil, lastError("create", name)
+	}
+	return &writer{name: name, fp: fp}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	defer errorScope()()
+	if w.fp == nil {
+		return 0, &fs.PathError{Op: "write", Path: w.name, Err: fs.ErrClosed}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := int(C.VSIFWriteL(unsafe.Pointer(&p[0]), 1, C.size_t(len(p)), w.fp))
+	if n < len(p) {
+		return n, lastError("write", w.name)
+	}
+	return n, nil
+}
+
+func (w *writer) Close() error {
+	defer errorScope()()
+	if w.fp == nil {
+		return &fs.PathError{Op: "close", Path: w.name, Err: fs.ErrClosed}
+	}
+	ret := C.VSIFCloseL(w.fp)
+	w.fp = nil
+	if ret != 0 {
+		return lastError("close", w.name)
+	}
+	return nil
+}
+
+// Create a directory at a VSI path
+func Mkdir(name string, perm fs.FileMode) error {
+	defer errorScope()()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.CPLErrorReset()
+	if C.VSIMkdir(cName, C.long(perm.Perm())) != 0 {
+		return lastError("mkdir", name)
+	}
+	return nil
+}
+
+// Remove the file or empty directory at a VSI path
+func Remove(name string) error {
+	defer errorScope()()
+	info, err := stat("remove", name, name)
+	if err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.CPLErrorReset()
+	var ret C.int
+	if info.IsDir() {
+		ret = C.VSIRmdir(cName)
+	} else {
+		ret = C.VSIUnlink(cName)
+	}
+	if ret != 0 {
+		return lastError("remove", name)
+	}
+	return nil
+}
+
+// Rename the file or directory at a VSI path
+func Rename(oldName, newName string) error {
+	defer errorScope()()
+	cOld := C.CString(oldName)
+	defer C.free(unsafe.Pointer(cOld))
+	cNew := C.CString(newName)
+	defer C.free(unsafe.Pointer(cNew))
+	C.CPLErrorReset()
+	if C.VSIRename(cOld, cNew) != 0 {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: lastErrorMsg()}
+	}
+	return nil
+}
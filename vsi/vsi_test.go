@@ -0,0 +1,111 @@
+package vsi
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func writeFile(t *testing.T, name string, data []byte) {
+	t.Helper()
+	w, err := Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestZipFS(t *testing.T) {
+	w, err := Create("/vsimem/vsi_test.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(w)
+	for _, name := range []string{"readme.txt", "scenes/a.tif", "scenes/b.tif"} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(f, "content of "+name)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	defer Remove("/vsimem/vsi_test.zip")
+
+	fsys := FS("/vsizip//vsimem/vsi_test.zip")
+	var files []string
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(files, []string{"readme.txt", "scenes/a.tif", "scenes/b.tif"}) {
+		t.Errorf("got files %v", files)
+	}
+
+	f, err := fsys.Open("scenes/b.tif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rsc := f.(io.ReadSeekCloser)
+	if _, err := rsc.Seek(-5, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(rsc); err != nil || string(b) != "b.tif" {
+		t.Errorf("got %q, %v", b, err)
+	}
+	if info, err := fs.Stat(fsys, "scenes/a.tif"); err != nil || info.Size() != int64(len("content of scenes/a.tif")) {
+		t.Errorf("got %v, %v", info, err)
+	}
+	if _, err := fsys.Open("missing.tif"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
+
+func TestMemFS(t *testing.T) {
+	if err := Mkdir("/vsimem/vsi_test", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Mkdir("/vsimem/vsi_test/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, "/vsimem/vsi_test/a.txt", []byte("hello"))
+	writeFile(t, "/vsimem/vsi_test/sub/b.txt", []byte("world"))
+	if err := Rename("/vsimem/vsi_test/sub/b.txt", "/vsimem/vsi_test/sub/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fstest.TestFS(FS("/vsimem/vsi_test"), "a.txt", "sub/c.txt"); err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{"/vsimem/vsi_test/sub/c.txt", "/vsimem/vsi_test/sub", "/vsimem/vsi_test/a.txt", "/vsimem/vsi_test"} {
+		if err := Remove(name); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := Remove("/vsimem/vsi_test"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+)
+
+/* ==================================================================== */
+/*      Go virtual file systems                                         */
+/* ==================================================================== */
+
+// Register a virtual file system handler, through which GDAL reads the files
+// of fsys under prefix.  Once "/vsigo/" is registered,
+// Open("/vsigo/bucket/scene.tif", ReadOnly) reads the file "bucket/scene.tif"
+// of fsys.
+//
+// Files implementing io.ReaderAt or io.Seeker are read by ranges, through a
+// cache of 64kB blocks; other files are read whole into memory when opened.
+// The file system is read-only, and must be safe for concurrent use if
+// datasets are read from several goroutines.  Handlers cannot be removed, and
+// registering a prefix again replaces its handler.
+func RegisterVSIHandler(prefix string, fsys fs.FS) error {
+	defer errorScope()()
+	if !strings.HasPrefix(prefix, "/vs") {
+		return fmt.Errorf("Error: invalid virtual file system prefix '%s'", prefix)
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+
+	h := cgo.NewHandle(fsys)
+	if C.goGDALInstallVSIHandler(cPrefix, C.uintptr_t(h)) != 0 {
+		h.Delete()
+		return lastError(CE_Failure)
+	}
+	return nil
+}
+
+// vsiFile is a file of a Go file system opened by GDAL
+type vsiFile struct {
+	r      io.ReaderAt
+	closer io.Closer
+	offset int64
+	size   int64
+	eof    bool
+}
+
+// readSeekerAt reads by ranges from a file that can only seek
+type readSeekerAt struct {
+	io.ReadSeeker
+}
+
+func (r readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// vsiName returns the name in the file system of a file name given by GDAL,
+// already stripped of the handler prefix
+func vsiName(filename *C.char) string {
+	name := strings.Trim(C.GoString(filename), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func openVSIFile(fsys fs.FS, name string) (*vsiFile, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, errors.New("is a directory")
+	}
+	switch r := f.(type) {
+	case io.ReaderAt:
+		return &vsiFile{r: r, closer: f, size: info.Size()}, nil
+	case io.ReadSeeker:
+		return &vsiFile{r: readSeekerAt{r}, closer: f, size: info.Size()}, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return &vsiFile{r: bytes.NewReader(data), size: int64(len(data))}, nil
+}
+
+//export goGDALVSIOpenProxyA
+func goGDALVSIOpenProxyA(handle C.uintptr_t, filename, access *C.char) C.uintptr_t {
+	if mode := C.GoString(access); strings.ContainsAny(mode, "wa+") {
+		return 0
+	}
+	fsys := cgo.Handle(handle).Value().(fs.FS)
+	f, err := openVSIFile(fsys, vsiName(filename))
+	if err != nil {
+		return 0
+	}
+	return C.uintptr_t(cgo.NewHandle(f))
+}
+
+//export goGDALVSIReadProxyA
+func goGDALVSIReadProxyA(handle C.uintptr_t, buf unsafe.Pointer, size C.size_t) C.size_t {
+	f := cgo.Handle(handle).Value().(*vsiFile)
+	n, err := f.r.ReadAt(unsafe.Slice((*byte)(buf), int(size)), f.offset)
+	f.offset += int64(n)
+	if err != nil && n < int(size) {
+		f.eof = true
+	}
+	return C.size_t(n)
+}
+
+//export goGDALVSISeekProxyA
+func goGDALVSISeekProxyA(handle C.uintptr_t, offset C.GIntBig, whence C.int) C.int {
+	f := cgo.Handle(handle).Value().(*vsiFile)
+	switch whence {
+	case C.SEEK_SET:
+		f.offset = int64(offset)
+	case C.SEEK_CUR:
+		f.offset += int64(offset)
+	case C.SEEK_END:
+		f.offset = f.size + int64(offset)
+	default:
+		return -1
+	}
+	f.eof = false
+	return 0
+}
+
+//export goGDALVSITellProxyA
+func goGDALVSITellProxyA(handle C.uintptr_t) C.GIntBig {
+	f := cgo.Handle(handle).Value().(*vsiFile)
+	return C.GIntBig(f.offset)
+}
+
+//export goGDALVSIEofProxyA
+func goGDALVSIEofProxyA(handle C.uintptr_t) C.int {
+	f := cgo.Handle(handle).Value().(*vsiFile)
+	return BoolToCInt(f.eof)
+}
+
+//export goGDALVSICloseProxyA
+func goGDALVSICloseProxyA(handle C.uintptr_t) C.int {
+	h := cgo.Handle(handle)
+	f := h.Value().(*vsiFile)
+	h.Delete()
+	if f.closer != nil && f.closer.Close() != nil {
+		return -1
+	}
+	return 0
+}
+
+//export goGDALVSIStatProxyA
+func goGDALVSIStatProxyA(handle C.uintptr_t, filename *C.char, size, mtime *C.GIntBig, isDir *C.int) C.int {
+	fsys := cgo.Handle(handle).Value().(fs.FS)
+	info, err := fs.Stat(fsys, vsiName(filename))
+	if err != nil {
+		return -1
+	}
+	*size = C.GIntBig(info.Size())
+	*mtime = C.GIntBig(info.ModTime().Unix())
+	*isDir = BoolToCInt(info.IsDir())
+	return 0
+}
+
+//export goGDALVSIReadDirProxyA
+func goGDALVSIReadDirProxyA(handle C.uintptr_t, dirname *C.char, maxFiles C.int) **C.char {
+	fsys := cgo.Handle(handle).Value().(fs.FS)
+	entries, err := fs.ReadDir(fsys, vsiName(dirname))
+	if err != nil {
+		return nil
+	}
+	if maxFiles > 0 && len(entries) > int(maxFiles) {
+		entries = entries[:maxFiles]
+	}
+	var list **C.char
+	for _, entry := range entries {
+		cName := C.CString(entry.Name())
+		list = C.CSLAddString(list, cName)
+		C.free(unsafe.Pointer(cName))
+	}
+	return list
+}
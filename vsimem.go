@@ -0,0 +1,181 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+/* ==================================================================== */
+/*      In-memory files                                                 */
+/* ==================================================================== */
+
+var vsimemCount uint64
+
+// tempVSIMemName returns a unique name under /vsimem/
+func tempVSIMemName() string {
+	return fmt.Sprintf("/vsimem/go-gdal-%d", atomic.AddUint64(&vsimemCount, 1))
+}
+
+// VSIMemFile is a file held in memory by GDAL under /vsimem/.  Its name can be
+// handed to any function expecting a file name, such as Open or
+// Driver.CreateCopy.
+type VSIMemFile struct {
+	name string
+}
+
+// Create an in-memory file holding a copy of data.  The name must start with
+// /vsimem/, an empty name is replaced by a unique one.  The file lives until
+// it is unlinked.
+func CreateVSIMemFile(name string, data []byte) (VSIMemFile, error) {
+	defer errorScope()()
+	if name == "" {
+		name = tempVSIMemName()
+	}
+	if !strings.HasPrefix(name, "/vsimem/") {
+		return VSIMemFile{}, fmt.Errorf("Error: '%s' is not a /vsimem/ file name", name)
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	// GDAL takes ownership of the buffer, and frees it on unlink
+	buf := (*C.GByte)(C.VSIMalloc(C.size_t(max(len(data), 1))))
+	if buf == nil {
+		return VSIMemFile{}, fmt.Errorf("Error: cannot allocate %d bytes for '%s'", len(data), name)
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(buf)), len(data)), data)
+	fp := C.VSIFileFromMemBuffer(cName, buf, C.vsi_l_offset(len(data)), C.TRUE)
+	if fp == nil {
+		C.VSIFree(unsafe.Pointer(buf))
+		return VSIMemFile{}, lastError(CE_Failure)
+	}
+	C.VSIFCloseL(fp)
+	return VSIMemFile{name}, nil
+}
+
+// Return the /vsimem/ name of the file
+func (f VSIMemFile) Name() string {
+	return f.name
+}
+
+// Fetch a copy of the content of the file
+func (f VSIMemFile) Bytes() ([]byte, error) {
+	var data []byte
+	err := f.read(func(b []byte) error {
+		data = append([]byte(nil), b...)
+		return nil
+	})
+	return data, err
+}
+
+// read calls fn with the content of the file, which is only valid during the
+// call
+func (f VSIMemFile) read(fn func([]byte) error) error {
+	cName := C.CString(f.name)
+	defer C.free(unsafe.Pointer(cName))
+	var length C.vsi_l_offset
+	p := C.VSIGetMemFileBuffer(cName, &length, C.FALSE)
+	if p == nil {
+		return &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrNotExist}
+	}
+	return fn(unsafe.Slice((*byte)(unsafe.Pointer(p)), int(length)))
+}
+
+// Remove the file from memory
+func (f VSIMemFile) Unlink() error {
+	cName := C.CString(f.name)
+	defer C.free(unsafe.Pointer(cName))
+	if C.VSIUnlink(cName) != 0 {
+		return &fs.PathError{Op: "unlink", Path: f.name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+// Open a dataset from its encoded content, such as the bytes of a GeoTIFF or
+// GeoJSON file.  The data is copied into a /vsimem/ file, which is removed
+// when the dataset is closed.  A nil list of drivers allows all of them.
+func OpenBytes(data []byte, access Access, drivers []string) (*Dataset, error) {
+	f, err := CreateVSIMemFile("", data)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := OpenEx(f.Name(), access, drivers, nil, nil)
+	if err != nil {
+		f.Unlink()
+		return nil, err
+	}
+	ds.life.addCleanup(func() { f.Unlink() })
+	return ds, nil
+}
+
+// Encode a dataset with the given driver, such as "PNG", "COG" or "GPKG", and
+// write the encoded bytes to w.  The copy is made in a temporary /vsimem/
+// directory, which is removed before returning.  Only formats encoded as a
+// single file are supported: an error is returned if the driver also wrote
+// sidecar files, such as the .hdr file of ENVI.  Georeferencing and metadata
+// that the format cannot hold are dropped instead of being written to a
+// .aux.xml file.
+func EncodeTo(w io.Writer, ds *Dataset, driver string, options []string) error {
+	defer errorScope()()
+	if ds.closed() {
+		return ErrClosed
+	}
+	drv, err := GetDriverByName(driver)
+	if err != nil {
+		return err
+	}
+	dir := tempVSIMemName()
+	cDir := C.CString(dir)
+	defer C.free(unsafe.Pointer(cDir))
+	if C.VSIMkdir(cDir, 0755) != 0 {
+		return lastError(CE_Failure)
+	}
+	defer C.VSIRmdirRecursive(cDir)
+
+	// the goroutine is locked to its thread by the error scope
+	cKey, cNo := C.CString("GDAL_PAM_ENABLED"), C.CString("NO")
+	defer C.free(unsafe.Pointer(cKey))
+	defer C.free(unsafe.Pointer(cNo))
+	var cOld *C.char
+	if old := C.CPLGetThreadLocalConfigOption(cKey, nil); old != nil {
+		cOld = C.CPLStrdup(old)
+		defer C.VSIFree(unsafe.Pointer(cOld))
+	}
+	C.CPLSetThreadLocalConfigOption(cKey, cNo)
+	defer C.CPLSetThreadLocalConfigOption(cKey, cOld)
+
+	name := dir + "/encoded"
+	if ext := drv.MetadataItem(DMD_EXTENSION, ""); ext != "" {
+		name += "." + ext
+	}
+	out := drv.CreateCopy(name, *ds, 0, options, nil, nil)
+	if out == nil {
+		return lastError(CE_Failure)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	files := C.VSIReadDir(cDir)
+	n := int(C.CSLCount(files))
+	C.CSLDestroy(files)
+	if n != 1 {
+		return fmt.Errorf("Error: the %s driver wrote %d files, a single one is required", driver, n)
+	}
+	return VSIMemFile{name}.read(func(b []byte) error {
+		_, err := w.Write(b)
+		return err
+	})
+}
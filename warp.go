@@ -0,0 +1,254 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// WarpOptions configures Warp and Dataset.WarpInto.  The zero value warps the
+// sources to the spatial reference of the first one, using nearest neighbour
+// resampling.
+type WarpOptions struct {
+	// Output format short name, used when creating a new file (GTiff if empty)
+	Format string
+	// Creation options of the output file
+	CreationOptions []string
+
+	// Source spatial reference, overriding the one of the sources
+	SrcSRS string
+	// Target spatial reference, in any form accepted by
+	// SpatialReference.SetFromUserInput()
+	DstSRS string
+
+	// Output resolution in target georeferenced units
+	XRes, YRes float64
+	// Output size in pixels, exclusive with XRes and YRes
+	Width, Height int
+	// Output extent in target georeferenced units: minX, minY, maxX, maxY.
+	// Ignored when all zero.
+	Bounds [4]float64
+
+	// Resampling algorithm
+	ResampleAlg ResampleAlg
+
+	// Cutline restricting the warped area, in the spatial reference assigned
+	// to the geometry
+	Cutline *Geometry
+	// Crop the output extent to the extent of the cutline
+	CropToCutline bool
+
+	// Nodata values of the source bands, one per band or a single one for all
+	SrcNoData []float64
+	// Nodata values of the output bands, one per band or a single one for all
+	DstNoData []float64
+
+	// Run the warping and the I/O in separate threads
+	Multithread bool
+	// Number of threads used by the warping kernel, -1 using all CPUs
+	NumThreads int
+
+	// Additional warp options (GDALWarpOptions::papszWarpOptions)
+	WarpOptions []string
+	// Additional gdalwarp command line switches, passed as is
+	Options []string
+
+	// Progress reporting
+	Progress     ProgressFunc
+	ProgressData interface{}
+}
+
+// args returns the gdalwarp command line switches matching the options.
+func (opts *WarpOptions) args(cutline string, newFile bool) []string {
+	var args []string
+	if newFile && opts.Format != "" {
+		args = append(args, "-of", opts.Format)
+	}
+	if newFile {
+		for _, co := range opts.CreationOptions {
+			args = append(args, "-co", co)
+		}
+	}
+	if opts.SrcSRS != "" {
+		args = append(args, "-s_srs", opts.SrcSRS)
+	}
+	if opts.DstSRS != "" {
+		args = append(args, "-t_srs", opts.DstSRS)
+	}
+	if opts.XRes != 0 || opts.YRes != 0 {
+		args = append(args, "-tr", formatFloat(opts.XRes), formatFloat(opts.YRes))
+	}
+	if opts.Width != 0 || opts.Height != 0 {
+		args = append(args, "-ts", strconv.Itoa(opts.Width), strconv.Itoa(opts.Height))
+	}
+	if opts.Bounds != [4]float64{} {
+		args = append(args, "-te")
+		for _, v := range opts.Bounds {
+			args = append(args, formatFloat(v))
+		}
+	}
+	args = append(args, "-r", opts.ResampleAlg.name())
+	if cutline != "" {
+		args = append(args, "-cutline", cutline)
+		if opts.CropToCutline {
+			args = append(args, "-crop_to_cutline")
+		}
+	}
+	if len(opts.SrcNoData) > 0 {
+		args = append(args, "-srcnodata", formatFloats(opts.SrcNoData))
+	}
+	if len(opts.DstNoData) > 0 {
+		args = append(args, "-dstnodata", formatFloats(opts.DstNoData))
+	}
+	if opts.Multithread {
+		args = append(args, "-multi")
+	}
+	switch {
+	case opts.NumThreads < 0:
+		args = append(args, "-wo", "NUM_THREADS=ALL_CPUS")
+	case opts.NumThreads > 0:
+		args = append(args, "-wo", "NUM_THREADS="+strconv.Itoa(opts.NumThreads))
+	}
+	for _, wo := range opts.WarpOptions {
+		args = append(args, "-wo", wo)
+	}
+	return append(args, opts.Options...)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatFloats(vals []float64) string {
+	s := make([]string, len(vals))
+	for i, v := range vals {
+		s[i] = formatFloat(v)
+	}
+	return strings.Join(s, " ")
+}
+
+var cutlineCount uint64
+
+// writeCutline stores the geometry as a GeoJSON file in /vsimem/, so that it
+// can be handed to GDALWarp.  The returned function removes the file.
+func writeCutline(geom Geometry) (string, func(), error) {
+	defer errorScope()()
+	drv := OGRDriverByName("GeoJSON")
+	if drv.cval == nil {
+		return "", nil, errors.New("GeoJSON driver not available")
+	}
+	name := fmt.Sprintf("/vsimem/go-gdal-cutline-%d.json", atomic.AddUint64(&cutlineCount, 1))
+	cleanup := func() {
+		cName := C.CString(name)
+		defer C.free(unsafe.Pointer(cName))
+		C.VSIUnlink(cName)
+	}
+
+	ds, ok := drv.Create(name, nil)
+	if !ok {
+		return "", nil, lastError(CE_Failure)
+	}
+	defer ds.Destroy()
+	layer, err := ds.CreateLayer("cutline", geom.SpatialReference(), geom.Type(), nil)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	if err := feature.SetGeometry(geom); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := layer.CreateFeature(&feature); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return name, cleanup, nil
+}
+
+// Warp reprojects and mosaics the source datasets into a new dataset, written
+// to dst.  This is the equivalent of the gdalwarp utility.
+func Warp(dst string, srcs []*Dataset, opts WarpOptions) (*Dataset, error) {
+	h, err := warp(dst, nil, srcs, &opts)
+	if err != nil {
+		return nil, err
+	}
+	return newDataset(h), nil
+}
+
+// WarpInto reprojects and mosaics the source datasets into the existing
+// dataset.  The format, creation options, extent, resolution and size options
+// do not apply, as they are fixed by the dataset.
+func (dataset *Dataset) WarpInto(srcs []*Dataset, opts WarpOptions) error {
+	if dataset.closed() {
+		return ErrClosed
+	}
+	_, err := warp("", dataset.cval, srcs, &opts)
+	return err
+}
+
+func warp(dst string, dstDS C.GDALDatasetH, srcs []*Dataset, opts *WarpOptions) (C.GDALDatasetH, error) {
+	defer errorScope()()
+	defer runtime.KeepAlive(srcs)
+	if len(srcs) == 0 {
+		return nil, errors.New("no source dataset to warp")
+	}
+
+	var cutline string
+	if opts.Cutline != nil {
+		name, cleanup, err := writeCutline(*opts.Cutline)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		cutline = name
+	}
+
+	cArgs, free := cStringList(opts.args(cutline, dstDS == nil))
+	defer free()
+	warpOpts := C.GDALWarpAppOptionsNew((**C.char)(unsafe.Pointer(&cArgs[0])), nil)
+	if warpOpts == nil {
+		return nil, lastError(CE_Failure)
+	}
+	defer C.GDALWarpAppOptionsFree(warpOpts)
+
+	cProgress, cArg, release := progressProxy(opts.Progress, opts.ProgressData)
+	defer release()
+	C.GDALWarpAppOptionsSetProgress(warpOpts, cProgress, cArg)
+
+	cSrcs := make([]C.GDALDatasetH, len(srcs))
+	for i, src := range srcs {
+		if src.closed() {
+			return nil, ErrClosed
+		}
+		cSrcs[i] = src.cval
+	}
+
+	var cDst *C.char
+	if dstDS == nil {
+		cDst = C.CString(dst)
+		defer C.free(unsafe.Pointer(cDst))
+	}
+
+	var usageError C.int
+	h := C.GDALWarp(cDst, dstDS, C.int(len(cSrcs)), &cSrcs[0], warpOpts, &usageError)
+	if h == nil {
+		return nil, utilityError(usageError)
+	}
+	return h, nil
+}
@@ -0,0 +1,164 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+
+#cgo linux  pkg-config: gdal
+#cgo darwin pkg-config: gdal
+#cgo windows LDFLAGS: -Lc:/gdal/release-1600-x64/lib -lgdal_i
+#cgo windows CFLAGS: -IC:/gdal/release-1600-x64/include
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// Numeric is the set of Go types that map directly onto a GDAL pixel data
+// type.  complex64 and complex128 hold the CInt16/CFloat32 and
+// CInt32/CFloat64 types respectively, GDAL converting the values as needed.
+// int8, int64 and uint64 require the Int8, Int64 and UInt64 types of recent
+// GDAL versions.
+type Numeric interface {
+	int8 | uint8 | uint16 | int16 | uint32 | int32 | int64 | uint64 |
+		float32 | float64 | complex64 | complex128
+}
+
+// DataTypeOf returns the GDAL data type matching T, Unknown if GDAL is too old
+// to have it
+func DataTypeOf[T Numeric]() DataType {
+	var zero T
+	switch any(zero).(type) {
+	case int8:
+		return Int8
+	case uint8:
+		return Byte
+	case uint16:
+		return UInt16
+	case int16:
+		return Int16
+	case uint32:
+		return UInt32
+	case int32:
+		return Int32
+	case int64:
+		return Int64
+	case uint64:
+		return UInt64
+	case float32:
+		return Float32
+	case float64:
+		return Float64
+	case complex64:
+		return CFloat32
+	case complex128:
+		return CFloat64
+	}
+	return Unknown
+}
+
+// dataTypeFor returns the GDAL data type matching T, or an error if GDAL is
+// too old to have it
+func dataTypeFor[T Numeric]() (DataType, error) {
+	dataType := DataTypeOf[T]()
+	if dataType == Unknown {
+		var zero T
+		return Unknown, fmt.Errorf("Error: %T pixels are not supported by GDAL %d.%d", zero, VERSION_MAJOR, VERSION_MINOR)
+	}
+	return dataType, nil
+}
+
+// ErrInvalidWindow is returned when a Window has a negative offset or an empty
+// size.
+var ErrInvalidWindow = errors.New("invalid raster window")
+
+// Window is a region of a raster, and the size of the buffer it is read into
+// or written from.  If BufXSize and BufYSize are zero the buffer has the size
+// of the region, otherwise GDAL resamples between the two.
+type Window struct {
+	XOff, YOff         int
+	XSize, YSize       int
+	BufXSize, BufYSize int
+}
+
+// BufferSize returns the size of the buffer that holds the window
+func (win Window) BufferSize() (int, int) {
+	bufXSize, bufYSize := win.BufXSize, win.BufYSize
+	if bufXSize == 0 && bufYSize == 0 {
+		bufXSize, bufYSize = win.XSize, win.YSize
+	}
+	return bufXSize, bufYSize
+}
+
+// Len returns the number of pixels in the buffer that holds the window
+func (win Window) Len() int {
+	bufXSize, bufYSize := win.BufferSize()
+	return bufXSize * bufYSize
+}
+
+func (win Window) validate() error {
+	bufXSize, bufYSize := win.BufferSize()
+	if win.XOff < 0 || win.YOff < 0 || win.XSize <= 0 || win.YSize <= 0 ||
+		bufXSize <= 0 || bufYSize <= 0 {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// ReadWindow reads a region of the band into a newly allocated slice, GDAL
+// converting the pixels to the data type matching T.
+func ReadWindow[T Numeric](band *RasterBand, win Window) ([]T, error) {
+	if err := win.validate(); err != nil {
+		return nil, err
+	}
+	buf := make([]T, win.Len())
+	if err := ReadWindowInto(band, win, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ReadWindowInto reads a region of the band into buf, which must hold exactly
+// the number of pixels of the window buffer.
+func ReadWindowInto[T Numeric](band *RasterBand, win Window, buf []T) error {
+	return windowIO(band, Read, win, buf)
+}
+
+// WriteWindow writes data into a region of the band, GDAL converting the
+// pixels from the data type matching T to the band data type.  data must hold
+// exactly the number of pixels of the window buffer.
+func WriteWindow[T Numeric](band *RasterBand, win Window, data []T) error {
+	return windowIO(band, Write, win, data)
+}
+
+func windowIO[T Numeric](band *RasterBand, rwFlag RWFlag, win Window, buf []T) error {
+	defer errorScope()()
+	defer runtime.KeepAlive(band)
+	if band.closed() {
+		return ErrClosed
+	}
+	if err := win.validate(); err != nil {
+		return err
+	}
+	if len(buf) != win.Len() {
+		return fmt.Errorf("Error: buffer holds %d pixels, window requires %d", len(buf), win.Len())
+	}
+	dataType, err := dataTypeFor[T]()
+	if err != nil {
+		return err
+	}
+	bufXSize, bufYSize := win.BufferSize()
+
+	return C.GDALRasterIO(
+		band.cval,
+		C.GDALRWFlag(rwFlag),
+		C.int(win.XOff), C.int(win.YOff), C.int(win.XSize), C.int(win.YSize),
+		unsafe.Pointer(&buf[0]),
+		C.int(bufXSize), C.int(bufYSize),
+		C.GDALDataType(dataType),
+		0, 0,
+	).Err()
+}